@@ -0,0 +1,123 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// s7profilegen generates a device profile from the DB sources exported by STEP 7 or TIA Portal,
+// with the absolute addresses of the variables of non-optimized DBs, and from the symbol tables
+// of STEP 7 Classic (.sdf, .seq, .asc) and the PLC tag tables of TIA Portal (.csv).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/edgexfoundry/device-s7/internal/symbols"
+)
+
+func main() {
+	name := flag.String("name", "", "name of the profile, the name of the first DB by default")
+	manufacturer := flag.String("manufacturer", "Siemens", "manufacturer of the profile")
+	model := flag.String("model", "S7", "model of the profile")
+	description := flag.String("description", "", "description of the profile")
+	dbNumbers := flag.String("db", "", "numbers of the DBs named by their symbol, like 10 for a single DB or Tank1=10,Tank2=11")
+	output := flag.String("o", "", "profile file to write, the standard output by default")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] source.db... symbols.sdf...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var sources []io.Reader
+	var symbolTable []symbols.Symbol
+	for _, path := range flag.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("open %s failed, error: %v", path, err)
+		}
+		defer file.Close()
+		format, ok := symbols.SymbolTableFormat(path)
+		if !ok {
+			sources = append(sources, file)
+			continue
+		}
+		table, err := symbols.ParseSymbolTable(file, format)
+		if err != nil {
+			log.Fatalf("parse symbol table %s failed, error: %v", path, err)
+		}
+		symbolTable = append(symbolTable, table...)
+	}
+	blocks, err := symbols.ParseDBSources(sources...)
+	if err != nil {
+		log.Fatalf("parse DB sources failed, error: %v", err)
+	}
+	numbers, err := parseDBNumbers(*dbNumbers, blocks)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, number := range symbolDBNumbers(symbolTable) {
+		if _, ok := numbers[name]; !ok {
+			numbers[name] = number
+		}
+	}
+
+	profile := &Profile{
+		Name:         *name,
+		Manufacturer: *manufacturer,
+		Description:  *description,
+		Model:        *model,
+		Labels:       []string{"ISO-on-TCP"},
+	}
+	if profile.Name == "" && len(blocks) > 0 {
+		profile.Name = blocks[0].Name
+	} else if profile.Name == "" {
+		profile.Name = strings.TrimSuffix(filepath.Base(flag.Arg(0)), filepath.Ext(flag.Arg(0)))
+	}
+	if profile.Description == "" {
+		profile.Description = "Generated from the DB sources and the symbol tables by s7profilegen"
+	}
+	resources, commands, skipped := symbolResources(symbolTable)
+	for _, symbol := range skipped {
+		log.Printf("symbol %s is skipped", symbol)
+	}
+	profile.DeviceResources, profile.DeviceCommands = resources, commands
+	for _, block := range blocks {
+		number, ok := numbers[block.Name]
+		if !ok {
+			log.Fatalf("DB %s has no number, set it with -db or add the symbol table", block.Name)
+		}
+		resources, skipped := dataBlockResources(block, number)
+		for _, variable := range skipped {
+			log.Printf("DB %s: variable %s is skipped, its data type is not supported", block.Name, variable)
+		}
+		if len(resources) == 0 {
+			continue
+		}
+		profile.DeviceResources = append(profile.DeviceResources, resources...)
+		profile.DeviceCommands = append(profile.DeviceCommands, newCommand(block.Name, resources))
+	}
+	if len(profile.DeviceResources) == 0 {
+		log.Fatalf("no variable found in %v", flag.Args())
+	}
+
+	w := os.Stdout
+	if *output != "" {
+		if w, err = os.Create(*output); err != nil {
+			log.Fatalf("create %s failed, error: %v", *output, err)
+		}
+		defer w.Close()
+	}
+	if err = writeProfile(w, profile); err != nil {
+		log.Fatalf("write profile failed, error: %v", err)
+	}
+}
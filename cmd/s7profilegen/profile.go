@@ -0,0 +1,309 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"gopkg.in/yaml.v3"
+
+	"github.com/edgexfoundry/device-s7/internal/driver"
+	"github.com/edgexfoundry/device-s7/internal/symbols"
+)
+
+// Profile is a device profile in the layout of the profiles of cmd/res/profiles
+type Profile struct {
+	Name            string     `yaml:"name"`
+	Manufacturer    string     `yaml:"manufacturer,omitempty"`
+	Description     string     `yaml:"description,omitempty"`
+	Model           string     `yaml:"model,omitempty"`
+	Labels          []string   `yaml:"labels,flow,omitempty"`
+	DeviceResources []Resource `yaml:"deviceResources"`
+	DeviceCommands  []Command  `yaml:"deviceCommands,omitempty"`
+}
+
+// Resource is a device resource of a PLC variable
+type Resource struct {
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description,omitempty"`
+	IsHidden    bool           `yaml:"isHidden"`
+	Properties  Properties     `yaml:"properties"`
+	Attributes  map[string]any `yaml:"attributes"`
+}
+
+// Properties are the value properties of a device resource
+type Properties struct {
+	ValueType string `yaml:"valueType"`
+	ReadWrite string `yaml:"readWrite"`
+	Units     string `yaml:"units,omitempty"`
+}
+
+// Command reads or writes the resources of a DB or an address area together
+type Command struct {
+	Name               string              `yaml:"name"`
+	IsHidden           bool                `yaml:"isHidden"`
+	ReadWrite          string              `yaml:"readWrite"`
+	ResourceOperations []ResourceOperation `yaml:"resourceOperations"`
+}
+
+// ResourceOperation is a resource of a device command
+type ResourceOperation struct {
+	DeviceResource string `yaml:"deviceResource"`
+}
+
+// valueType is the EdgeX value of an S7 data type
+type valueType struct {
+	valueType string
+	units     string
+	encoding  string
+}
+
+// valueTypes of the S7 data types read by the driver, the strings and the date and time structures are not
+var valueTypes = map[string]valueType{
+	"BOOL":         {valueType: common.ValueTypeBool},
+	"BYTE":         {valueType: common.ValueTypeUint8},
+	"CHAR":         {valueType: common.ValueTypeUint8},
+	"USINT":        {valueType: common.ValueTypeUint8},
+	"SINT":         {valueType: common.ValueTypeInt8},
+	"WORD":         {valueType: common.ValueTypeUint16},
+	"UINT":         {valueType: common.ValueTypeUint16},
+	"DATE":         {valueType: common.ValueTypeUint16, units: "days since 1990-01-01"},
+	"INT":          {valueType: common.ValueTypeInt16},
+	"S5TIME":       {valueType: common.ValueTypeInt32, units: "ms", encoding: "S5TIME"},
+	"DWORD":        {valueType: common.ValueTypeUint32},
+	"UDINT":        {valueType: common.ValueTypeUint32},
+	"TIME_OF_DAY":  {valueType: common.ValueTypeUint32, units: "ms since midnight"},
+	"TOD":          {valueType: common.ValueTypeUint32, units: "ms since midnight"},
+	"DINT":         {valueType: common.ValueTypeInt32},
+	"TIME":         {valueType: common.ValueTypeInt32, units: "ms"},
+	"REAL":         {valueType: common.ValueTypeFloat32},
+	"LREAL":        {valueType: common.ValueTypeFloat64},
+	"LINT":         {valueType: common.ValueTypeInt64},
+	"LTIME":        {valueType: common.ValueTypeInt64, units: "ns"},
+	"ULINT":        {valueType: common.ValueTypeUint64},
+	"LWORD":        {valueType: common.ValueTypeUint64},
+	"LTIME_OF_DAY": {valueType: common.ValueTypeUint64, units: "ns since midnight"},
+	"LTOD":         {valueType: common.ValueTypeUint64, units: "ns since midnight"},
+}
+
+// transliterations of the German letters in the resource names
+var nameTransliterations = map[rune]string{
+	'ä': "ae", 'ö': "oe", 'ü': "ue", 'Ä': "Ae", 'Ö': "Oe", 'Ü': "Ue", 'ß': "ss",
+}
+
+// resourceName converts a symbol or variable name to a resource name of the RFC 3986 unreserved characters
+// checked by core-metadata, like Motor_1_Run for "Motor 1 Run". The other characters are replaced by an
+// underscore, and the result is empty when no letter or digit is left.
+func resourceName(name string) string {
+	var builder strings.Builder
+	valid := false
+	replaced := false
+	for _, r := range name {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			valid = true
+			builder.WriteRune(r)
+		case r == '-', r == '.', r == '_', r == '~':
+			builder.WriteRune(r)
+		case nameTransliterations[r] != "":
+			valid = true
+			builder.WriteString(nameTransliterations[r])
+		default:
+			// a single underscore for a run of invalid characters
+			if !replaced {
+				builder.WriteByte('_')
+			}
+			replaced = true
+			continue
+		}
+		replaced = false
+	}
+	if !valid {
+		return ""
+	}
+	return builder.String()
+}
+
+// newResource returns the resource of a variable at an address, or false for the data types the driver can't read
+func newResource(name string, dataType string, address string, readOnly bool, comment string) (Resource, bool) {
+	vt, ok := valueTypes[strings.ToUpper(dataType)]
+	if !ok {
+		return Resource{}, false
+	}
+	resource := Resource{
+		Name:        name,
+		Description: comment,
+		Properties:  Properties{ValueType: vt.valueType, ReadWrite: common.ReadWrite_RW, Units: vt.units},
+		Attributes:  map[string]any{"NodeName": address},
+	}
+	if resource.Description == "" {
+		resource.Description = strings.ToUpper(dataType) + " " + name
+	}
+	if readOnly {
+		resource.Properties.ReadWrite = common.ReadWrite_R
+	}
+	if vt.encoding != "" {
+		resource.Attributes[driver.ENCODING] = vt.encoding
+	}
+	return resource, true
+}
+
+// newCommand returns the command of the resources, it is read-only when one of them is
+func newCommand(name string, resources []Resource) Command {
+	command := Command{Name: name, ReadWrite: common.ReadWrite_RW}
+	for _, resource := range resources {
+		if resource.Properties.ReadWrite == common.ReadWrite_R {
+			command.ReadWrite = common.ReadWrite_R
+		}
+		command.ResourceOperations = append(command.ResourceOperations, ResourceOperation{DeviceResource: resource.Name})
+	}
+	return command
+}
+
+// dataBlockResources returns the resources of the variables of a DB, named after the DB and the variable
+// like Tank1.Level, and the variables of the data types which are skipped
+func dataBlockResources(db symbols.DataBlock, number int) (resources []Resource, skipped []string) {
+	for _, v := range db.Variables {
+		name := db.Name + "." + v.Name
+		resource, ok := newResource(resourceName(name), v.DataType, v.Address(number), v.ReadOnly, v.Comment)
+		if !ok {
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", name, v.DataType))
+			continue
+		}
+		resources = append(resources, resource)
+	}
+	return resources, skipped
+}
+
+// parseDBNumbers parses the DB numbers of the -db flag, like "10" for a single DB or "Tank1=10,Tank2=11"
+func parseDBNumbers(value string, blocks []symbols.DataBlock) (map[string]int, error) {
+	numbers := make(map[string]int)
+	for _, block := range blocks {
+		if block.Number > 0 {
+			numbers[block.Name] = block.Number
+		}
+	}
+	if value == "" {
+		return numbers, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		if len(blocks) != 1 {
+			return nil, fmt.Errorf("a single DB number is given for %d DBs, set them like Tank1=10,Tank2=11", len(blocks))
+		}
+		numbers[blocks[0].Name] = n
+		return numbers, nil
+	}
+	for _, entry := range strings.Split(value, ",") {
+		name, number, found := strings.Cut(entry, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(number))
+		if !found || err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid DB number %q, set them like Tank1=10,Tank2=11", entry)
+		}
+		numbers[strings.Trim(strings.TrimSpace(name), `"`)] = n
+	}
+	return numbers, nil
+}
+
+// writeProfile writes the profile as YAML
+func writeProfile(w io.Writer, profile *Profile) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(profile); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// areaCommands are the device commands of the address areas, in the order of the profile
+var areaCommands = []struct {
+	area int
+	name string
+}{
+	{area: 0x81, name: "Inputs"},
+	{area: 0x82, name: "Outputs"},
+	{area: 0x83, name: "Merkers"},
+	{area: 0x84, name: "DataBlocks"},
+}
+
+// symbolResources returns the resources of the symbols, with a device command per address area,
+// and the symbols which are skipped with the reason. The symbols of the blocks are skipped silently,
+// the ones of the DBs give their numbers to the DB sources.
+func symbolResources(symbolTable []symbols.Symbol) (resources []Resource, commands []Command, skipped []string) {
+	areas := make(map[int][]Resource)
+	names := make(map[string]string)
+	for _, symbol := range symbolTable {
+		switch symbol.DataType {
+		case "DB", "FB", "FC", "OB", "SFB", "SFC", "UDT", "VAT":
+			continue
+		}
+		dbInfo, err := driver.ParseAddress(symbol.Address)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s (%s): %v", symbol.Name, symbol.Address, err))
+			continue
+		}
+		// the word lengths of bits, bytes and words, the other addresses are double words
+		dataType, addressSize := "DWORD", 4
+		switch dbInfo.WordLength {
+		case 0x01:
+			dataType, addressSize = "BOOL", 0
+		case 0x02:
+			dataType, addressSize = "BYTE", 1
+		case 0x04:
+			dataType, addressSize = "WORD", 2
+		}
+		if symbol.DataType != "" {
+			dataType = symbol.DataType
+		}
+		// the 64-bit values are the 8 bytes from the start of a double word address
+		if size, ok := symbols.DataTypeSize(dataType); ok && size != addressSize && !(size == 8 && addressSize == 4) {
+			skipped = append(skipped, fmt.Sprintf("%s (%s): %s doesn't fit the address", symbol.Name, symbol.Address, dataType))
+			continue
+		}
+		name := resourceName(symbol.Name)
+		if name == "" {
+			skipped = append(skipped, fmt.Sprintf("%s (%s): no resource name can be made of the symbol name", symbol.Name, symbol.Address))
+			continue
+		}
+		if other, ok := names[name]; ok {
+			skipped = append(skipped, fmt.Sprintf("%s (%s): resource name %s is taken by %s", symbol.Name, symbol.Address, name, other))
+			continue
+		}
+		resource, ok := newResource(name, dataType, symbol.Address, dbInfo.Area == 0x81, symbol.Comment)
+		if !ok {
+			skipped = append(skipped, fmt.Sprintf("%s (%s): %s is not supported", symbol.Name, symbol.Address, dataType))
+			continue
+		}
+		names[name] = symbol.Name
+		areas[dbInfo.Area] = append(areas[dbInfo.Area], resource)
+	}
+	for _, area := range areaCommands {
+		if len(areas[area.area]) == 0 {
+			continue
+		}
+		resources = append(resources, areas[area.area]...)
+		commands = append(commands, newCommand(area.name, areas[area.area]))
+	}
+	return resources, commands, skipped
+}
+
+// symbolDBNumbers returns the DB numbers of the DB symbols, like Tank1 for DB10
+func symbolDBNumbers(symbolTable []symbols.Symbol) map[string]int {
+	numbers := make(map[string]int)
+	for _, symbol := range symbolTable {
+		if symbol.DataType != "DB" {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(symbol.Address, "DB")); err == nil {
+			numbers[symbol.Name] = n
+		}
+	}
+	return numbers
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/dtos"
+	"gopkg.in/yaml.v3"
+
+	"github.com/edgexfoundry/device-s7/internal/symbols"
+)
+
+const source = `DATA_BLOCK "Tank1"
+{ S7_Optimized_Access := 'FALSE' }
+VERSION : 0.1
+   STRUCT
+      Level { ExternalWritable := 'False'} : Real;   // level in m
+      Running : Bool;
+      Delay : S5Time;
+      Name : String[10];
+   END_STRUCT;
+BEGIN
+END_DATA_BLOCK
+`
+
+func TestDataBlockProfile(t *testing.T) {
+	blocks, err := symbols.ParseDBSources(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("ParseDBSources() error = %v", err)
+	}
+	numbers, err := parseDBNumbers("Tank1=10", blocks)
+	if err != nil || numbers["Tank1"] != 10 {
+		t.Fatalf("parseDBNumbers() = %v, %v", numbers, err)
+	}
+
+	resources, skipped := dataBlockResources(blocks[0], numbers["Tank1"])
+	if len(resources) != 3 || len(skipped) != 1 || skipped[0] != "Tank1.Name (STRING[10])" {
+		t.Fatalf("resources = %+v, skipped = %v", resources, skipped)
+	}
+	level := resources[0]
+	if level.Name != "Tank1.Level" || level.Attributes["NodeName"] != "DB10.DBD0" || level.Properties.ValueType != common.ValueTypeFloat32 ||
+		level.Properties.ReadWrite != common.ReadWrite_R || level.Description != "level in m" {
+		t.Errorf("resource Level = %+v", level)
+	}
+	if delay := resources[2]; delay.Attributes["NodeName"] != "DB10.DBW6" || delay.Attributes["Encoding"] != "S5TIME" {
+		t.Errorf("resource Delay = %+v", delay)
+	}
+
+	profile := &Profile{Name: "Tank", DeviceResources: resources, DeviceCommands: []Command{newCommand("Tank1", resources)}}
+	var buffer bytes.Buffer
+	if err = writeProfile(&buffer, profile); err != nil {
+		t.Fatalf("writeProfile() error = %v", err)
+	}
+	var dto dtos.DeviceProfile
+	if err = yaml.Unmarshal(buffer.Bytes(), &dto); err != nil {
+		t.Fatalf("unmarshal profile error = %v", err)
+	}
+	if err = dto.Validate(); err != nil {
+		t.Errorf("generated profile is invalid, error: %v\n%s", err, buffer.String())
+	}
+	if dto.DeviceCommands[0].ReadWrite != common.ReadWrite_R {
+		t.Errorf("command of a read-only resource is %s", dto.DeviceCommands[0].ReadWrite)
+	}
+}
+
+func TestParseDBNumbers(t *testing.T) {
+	blocks := []symbols.DataBlock{{Name: "DB4", Number: 4}, {Name: "Tank1"}}
+	if _, err := parseDBNumbers("10", blocks); err == nil {
+		t.Errorf("a single number for two DBs should fail")
+	}
+	if _, err := parseDBNumbers("Tank1:10", blocks); err == nil {
+		t.Errorf("an invalid entry should fail")
+	}
+	numbers, err := parseDBNumbers(`"Tank1"=10`, blocks)
+	if err != nil || numbers["DB4"] != 4 || numbers["Tank1"] != 10 {
+		t.Errorf("parseDBNumbers() = %v, %v", numbers, err)
+	}
+}
+
+func TestSymbolResources(t *testing.T) {
+	table := "\"Start\",\"I       0.0\",\"BOOL\",\"Start button\"\n" +
+		"\"Motor1_Run\",\"Q       4.0\",\"BOOL\",\"\"\n" +
+		"\"Speed\",\"MW     10\",\"INT\",\"\"\n" +
+		"\"F\xFCllstand Tank/1\",\"MW     12\",\"INT\",\"\"\n" +
+		"\"Motor 1 Run\",\"Q       4.1\",\"BOOL\",\"\"\n" +
+		"\"Motor/1 Run\",\"Q       4.2\",\"BOOL\",\"\"\n" +
+		"\"###\",\"Q       4.3\",\"BOOL\",\"\"\n" +
+		"\"Total\",\"MD     14\",\"LREAL\",\"\"\n" +
+		"\"Level\",\"MW     20\",\"REAL\",\"\"\n" +
+		"\"Analog\",\"PIW   256\",\"INT\",\"\"\n" +
+		"\"Tank1\",\"DB     10\",\"DB     10\",\"\"\n" +
+		"\"Control\",\"FB      1\",\"FB      1\",\"\"\n"
+	symbolTable, err := symbols.ParseSymbolTable(strings.NewReader(table), symbols.FormatSDF)
+	if err != nil {
+		t.Fatalf("ParseSymbolTable() error = %v", err)
+	}
+
+	resources, commands, skipped := symbolResources(symbolTable)
+	names := make([]string, len(resources))
+	for i, resource := range resources {
+		names[i] = resource.Name + "=" + resource.Attributes["NodeName"].(string) + ":" + resource.Properties.ReadWrite
+	}
+	if got := strings.Join(names, " "); got != "Start=I0.0:R Motor1_Run=Q4.0:RW Motor_1_Run=Q4.1:RW Speed=MW10:RW Fuellstand_Tank_1=MW12:RW Total=MD14:RW" {
+		t.Errorf("resources = %s", got)
+	}
+	if len(commands) != 3 || commands[0].Name != "Inputs" || commands[0].ReadWrite != common.ReadWrite_R ||
+		commands[2].Name != "Merkers" || len(commands[2].ResourceOperations) != 3 {
+		t.Errorf("commands = %+v", commands)
+	}
+	if len(skipped) != 4 || !strings.Contains(skipped[0], "resource name Motor_1_Run is taken by Motor 1 Run") ||
+		!strings.HasPrefix(skipped[1], "### (Q4.3)") || !strings.HasPrefix(skipped[2], "Level") || !strings.HasPrefix(skipped[3], "Analog") {
+		t.Errorf("skipped = %v", skipped)
+	}
+	if numbers := symbolDBNumbers(symbolTable); len(numbers) != 1 || numbers["Tank1"] != 10 {
+		t.Errorf("symbolDBNumbers() = %v", numbers)
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	for name, want := range map[string]string{
+		"Motor1_Run":       "Motor1_Run",
+		"Motor 1 Run":      "Motor_1_Run",
+		"Füllstand Tank/1": "Fuellstand_Tank_1",
+		"Tank1.Level":      "Tank1.Level",
+		"Ventil (Zulauf)":  "Ventil_Zulauf_",
+		"Straße~2":         "Strasse~2",
+		"---":              "",
+	} {
+		if got := resourceName(name); got != want {
+			t.Errorf("resourceName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Animation types
+const (
+	animationRamp    = "ramp"
+	animationSine    = "sine"
+	animationCounter = "counter"
+	animationToggle  = "toggle"
+)
+
+// Animation changes a value over time
+//   - ramp: rises from Min to Max during Period and starts again
+//   - sine: swings between Min and Max with the Period
+//   - counter: adds Step every Period and wraps from Max to Min
+//   - toggle: inverts a Bool every Period
+type Animation struct {
+	Type   string        `yaml:"Type"`
+	Min    float64       `yaml:"Min"`
+	Max    float64       `yaml:"Max"`
+	Step   float64       `yaml:"Step"`
+	Period time.Duration `yaml:"Period"`
+}
+
+// validate checks the animation of a value type and sets the defaults
+func (a *Animation) validate(valueType string) error {
+	a.Type = strings.ToLower(a.Type)
+	switch a.Type {
+	case animationRamp, animationSine:
+		if a.Max <= a.Min {
+			return fmt.Errorf("%s Max %v should be greater than Min %v", a.Type, a.Max, a.Min)
+		}
+		if a.Period <= 0 {
+			a.Period = 10 * time.Second
+		}
+	case animationCounter:
+		if a.Step == 0 {
+			a.Step = 1
+		}
+		if a.Period <= 0 {
+			a.Period = time.Second
+		}
+	case animationToggle:
+		if valueType != "Bool" {
+			return fmt.Errorf("toggle animates Bool values only")
+		}
+		if a.Period <= 0 {
+			a.Period = time.Second
+		}
+		return nil
+	default:
+		return fmt.Errorf("animation %s should be ramp, sine, counter or toggle", a.Type)
+	}
+	if valueType == "Bool" {
+		return fmt.Errorf("%s animates numeric values only", a.Type)
+	}
+	return nil
+}
+
+// value returns the animated value after the elapsed time, Bool values are 0 or 1
+func (a *Animation) value(elapsed time.Duration, initial float64) float64 {
+	periods := float64(elapsed) / float64(a.Period)
+	switch a.Type {
+	case animationRamp:
+		return a.Min + (a.Max-a.Min)*(periods-math.Floor(periods))
+	case animationSine:
+		return a.Min + (a.Max-a.Min)*(1+math.Sin(2*math.Pi*periods))/2
+	case animationCounter:
+		value := initial + a.Step*math.Floor(periods)
+		if a.Max > a.Min {
+			span := a.Max - a.Min + math.Abs(a.Step)
+			value = a.Min + math.Mod(math.Mod(value-a.Min, span)+span, span)
+		}
+		return value
+	case animationToggle:
+		if int64(periods)%2 == 1 {
+			return 1 - initial
+		}
+		return initial
+	default:
+		return initial
+	}
+}
+
+// encode converts a numeric value to the big endian bytes of the value type
+func encode(valueType string, value float64) []byte {
+	switch valueType {
+	case "Int8", "Uint8":
+		return []byte{byte(int64(math.Round(value)))}
+	case "Int16", "Uint16":
+		return binary.BigEndian.AppendUint16(nil, uint16(int64(math.Round(value))))
+	case "Int32", "Uint32":
+		return binary.BigEndian.AppendUint32(nil, uint32(int64(math.Round(value))))
+	case "Float32":
+		return binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(value)))
+	default:
+		return nil
+	}
+}
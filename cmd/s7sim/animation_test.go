@@ -0,0 +1,80 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAnimationValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		animation Animation
+		valueType string
+		elapsed   time.Duration
+		initial   float64
+		want      float64
+	}{
+		{name: "ramp start", animation: Animation{Type: "ramp", Min: 0, Max: 100, Period: 10 * time.Second}, valueType: "Int16", want: 0},
+		{name: "ramp middle", animation: Animation{Type: "ramp", Min: 0, Max: 100, Period: 10 * time.Second}, valueType: "Int16", elapsed: 5 * time.Second, want: 50},
+		{name: "ramp wraps", animation: Animation{Type: "ramp", Min: 0, Max: 100, Period: 10 * time.Second}, valueType: "Int16", elapsed: 12 * time.Second, want: 20},
+		{name: "sine quarter", animation: Animation{Type: "sine", Min: -10, Max: 10, Period: 4 * time.Second}, valueType: "Float32", elapsed: time.Second, want: 10},
+		{name: "counter", animation: Animation{Type: "counter", Step: 2, Period: time.Second}, valueType: "Int32", elapsed: 3 * time.Second, initial: 5, want: 11},
+		{name: "counter wraps", animation: Animation{Type: "counter", Min: 0, Max: 3, Period: time.Second}, valueType: "Uint8", elapsed: 5 * time.Second, want: 1},
+		{name: "toggle", animation: Animation{Type: "toggle", Period: time.Second}, valueType: "Bool", elapsed: 1500 * time.Millisecond, want: 1},
+		{name: "toggle back", animation: Animation{Type: "toggle", Period: time.Second}, valueType: "Bool", elapsed: 2 * time.Second, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.animation.validate(tt.valueType); err != nil {
+				t.Fatalf("validate() error = %v", err)
+			}
+			if got := tt.animation.value(tt.elapsed, tt.initial); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnimationValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		animation Animation
+		valueType string
+	}{
+		{name: "unknown type", animation: Animation{Type: "square"}, valueType: "Int16"},
+		{name: "ramp without range", animation: Animation{Type: "ramp", Min: 5, Max: 5}, valueType: "Int16"},
+		{name: "toggle of a number", animation: Animation{Type: "toggle"}, valueType: "Int16"},
+		{name: "ramp of a Bool", animation: Animation{Type: "ramp", Max: 1}, valueType: "Bool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.animation.validate(tt.valueType); err == nil {
+				t.Errorf("validate() should fail")
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := loadConfig("s7sim.yaml")
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if len(config.DataBlocks) != 2 || config.Interval != 100*time.Millisecond {
+		t.Errorf("loadConfig() = %+v", config)
+	}
+
+	invalid := []Value{
+		{Address: "DBW2", Type: "Int32"},
+		{Address: "DBX2", Type: "Bool"},
+		{Address: "DBB1.1", Type: "Uint8"},
+		{Address: "DBD254", Type: "Float32"},
+		{Address: "DBW2", Type: "Int16", Value: "text"},
+	}
+	for _, value := range invalid {
+		if err := value.parse(256); err == nil {
+			t.Errorf("parse() of %+v should fail", value)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the layout and behavior of the simulated PLC
+type Config struct {
+	Address    string        `yaml:"Address"`
+	Interval   time.Duration `yaml:"Interval"`
+	Module     Module        `yaml:"Module"`
+	DataBlocks []DataBlock   `yaml:"DataBlocks"`
+}
+
+// Module is the identification answered by the SZL reads
+type Module struct {
+	OrderNumber  string `yaml:"OrderNumber"`
+	ModuleName   string `yaml:"ModuleName"`
+	SerialNumber string `yaml:"SerialNumber"`
+}
+
+// DataBlock is a DB with its initial and animated values
+type DataBlock struct {
+	Number int     `yaml:"Number"`
+	Size   int     `yaml:"Size"`
+	Values []Value `yaml:"Values"`
+}
+
+// Value is a variable of a DB, the address is relative to the DB like DBX0.0, DBB1, DBW2 or DBD4
+type Value struct {
+	Address   string     `yaml:"Address"`
+	Type      string     `yaml:"Type"`
+	Value     any        `yaml:"Value"`
+	Animation *Animation `yaml:"Animation"`
+
+	start int
+	bit   int
+	size  int
+}
+
+// Default values of the configuration
+const (
+	defaultAddress  = "0.0.0.0:102"
+	defaultInterval = 100 * time.Millisecond
+	defaultDBSize   = 256
+)
+
+var valueAddressPattern = regexp.MustCompile(`^DB([XBWD])(\d+)(?:\.([0-7]))?$`)
+
+// Bytes of the value types
+var typeSizes = map[string]int{
+	"Bool":    0,
+	"Int8":    1,
+	"Uint8":   1,
+	"Int16":   2,
+	"Uint16":  2,
+	"Int32":   4,
+	"Uint32":  4,
+	"Float32": 4,
+}
+
+// Bytes of the address sizes, bits are 0
+var addressSizes = map[string]int{"X": 0, "B": 1, "W": 2, "D": 4}
+
+// loadConfig reads and validates the YAML configuration file
+func loadConfig(path string) (*Config, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err = yaml.Unmarshal(contents, config); err != nil {
+		return nil, fmt.Errorf("parse %s failed, error: %v", path, err)
+	}
+	if err = config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s, error: %v", path, err)
+	}
+	return config, nil
+}
+
+// validate sets the defaults and parses the value addresses
+func (c *Config) validate() error {
+	if c.Address == "" {
+		c.Address = defaultAddress
+	}
+	if c.Interval <= 0 {
+		c.Interval = defaultInterval
+	}
+	numbers := make(map[int]bool)
+	for i := range c.DataBlocks {
+		db := &c.DataBlocks[i]
+		if db.Number < 1 || db.Number > 0xFFFF {
+			return fmt.Errorf("DB number %d should be 1 to 65535", db.Number)
+		}
+		if numbers[db.Number] {
+			return fmt.Errorf("DB%d is defined twice", db.Number)
+		}
+		numbers[db.Number] = true
+		if db.Size == 0 {
+			db.Size = defaultDBSize
+		}
+		if db.Size < 0 || db.Size > 0xFFFF {
+			return fmt.Errorf("size %d of DB%d should be 1 to 65535", db.Size, db.Number)
+		}
+		for j := range db.Values {
+			if err := db.Values[j].parse(db.Size); err != nil {
+				return fmt.Errorf("DB%d value %s: %v", db.Number, db.Values[j].Address, err)
+			}
+		}
+	}
+	return nil
+}
+
+// parse checks the address and type of the value
+func (v *Value) parse(dbSize int) error {
+	match := valueAddressPattern.FindStringSubmatch(strings.ToUpper(strings.ReplaceAll(v.Address, " ", "")))
+	if match == nil {
+		return fmt.Errorf("address should be like DBX0.0, DBB1, DBW2 or DBD4")
+	}
+	if (match[1] == "X") != (match[3] != "") {
+		return fmt.Errorf("only DBX addresses have a bit")
+	}
+	size, ok := typeSizes[v.Type]
+	if !ok {
+		return fmt.Errorf("type %s should be Bool, Int8, Uint8, Int16, Uint16, Int32, Uint32 or Float32", v.Type)
+	}
+	if size != addressSizes[match[1]] {
+		return fmt.Errorf("type %s does not fit the address size", v.Type)
+	}
+	v.start, _ = strconv.Atoi(match[2])
+	v.bit, _ = strconv.Atoi(match[3])
+	v.size = size
+	if v.start+max(size, 1) > dbSize {
+		return fmt.Errorf("address exceeds the DB size %d", dbSize)
+	}
+	if v.Value != nil {
+		if v.Type == "Bool" {
+			if _, err := cast.ToBoolE(v.Value); err != nil {
+				return fmt.Errorf("value %v is not a Bool", v.Value)
+			}
+		} else if _, err := cast.ToFloat64E(v.Value); err != nil {
+			return fmt.Errorf("value %v is not a number", v.Value)
+		}
+	}
+	if v.Animation != nil {
+		return v.Animation.validate(v.Type)
+	}
+	return nil
+}
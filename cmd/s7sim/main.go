@@ -0,0 +1,112 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// s7sim serves simulated S7 data blocks for integration tests without a PLC.
+// The DB layouts, initial values and animations are loaded from a YAML file.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func main() {
+	configFile := flag.String("config", "s7sim.yaml", "YAML file of the data blocks and animations")
+	port := flag.Int("port", 0, "TCP port to listen, overrides the port of the configured Address")
+	flag.Parse()
+
+	config, err := loadConfig(*configFile)
+	if err != nil {
+		log.Fatalf("load configuration failed, error: %v", err)
+	}
+	if *port > 0 {
+		host, _, err := net.SplitHostPort(config.Address)
+		if err != nil {
+			log.Fatalf("invalid Address %s, error: %v", config.Address, err)
+		}
+		config.Address = net.JoinHostPort(host, strconv.Itoa(*port))
+	}
+
+	server := s7server.NewServer()
+	for _, db := range config.DataBlocks {
+		server.SetDB(db.Number, make([]byte, db.Size))
+		for _, value := range db.Values {
+			writeValue(server, db.Number, value, initialValue(value))
+		}
+	}
+	setModule(server, config.Module)
+
+	if err = server.Start(config.Address); err != nil {
+		log.Fatalf("listen on %s failed, error: %v", config.Address, err)
+	}
+	log.Printf("S7 simulator listening on %s with %d data blocks", server.Addr(), len(config.DataBlocks))
+
+	done := make(chan struct{})
+	go animate(server, config, done)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+	close(done)
+	server.Stop()
+	log.Printf("S7 simulator stopped")
+}
+
+// animate updates the animated values every interval until done is closed
+func animate(server *s7server.Server, config *Config, done <-chan struct{}) {
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+	start := time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			for _, db := range config.DataBlocks {
+				for _, value := range db.Values {
+					if value.Animation == nil {
+						continue
+					}
+					writeValue(server, db.Number, value, value.Animation.value(now.Sub(start), initialValue(value)))
+				}
+			}
+		}
+	}
+}
+
+// initialValue returns the configured value as a number, Bool values are 0 or 1
+func initialValue(value Value) float64 {
+	if value.Type == "Bool" {
+		if cast.ToBool(value.Value) {
+			return 1
+		}
+		return 0
+	}
+	return cast.ToFloat64(value.Value)
+}
+
+// writeValue writes the value into the DB
+func writeValue(server *s7server.Server, db int, value Value, number float64) {
+	var err error
+	if value.Type == "Bool" {
+		err = server.WriteBit(s7server.AreaDB, db, value.start, value.bit, number != 0)
+	} else {
+		err = server.WriteArea(s7server.AreaDB, db, value.start, encode(value.Type, number))
+	}
+	if err != nil {
+		log.Printf("write DB%d.%s failed, error: %v", db, value.Address, err)
+	}
+}
@@ -0,0 +1,82 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/binary"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+// SZL IDs answered by the simulator
+const (
+	szlModuleIdentification    = 0x0011
+	szlComponentIdentification = 0x001C
+	szlOperatingMode           = 0x0424
+)
+
+// Defaults of the module identification
+const (
+	defaultOrderNumber  = "6ES7 315-2EH14-0AB0"
+	defaultModuleName   = "CPU 315-2 PN/DP"
+	defaultSerialNumber = "S7SIM-0001"
+)
+
+// setModule sets the SZL lists of the module identification and the RUN operating mode
+func setModule(server *s7server.Server, module Module) {
+	if module.OrderNumber == "" {
+		module.OrderNumber = defaultOrderNumber
+	}
+	if module.ModuleName == "" {
+		module.ModuleName = defaultModuleName
+	}
+	if module.SerialNumber == "" {
+		module.SerialNumber = defaultSerialNumber
+	}
+
+	// module (0x0001), hardware (0x0006) and firmware (0x0007) with the order number
+	var identification []byte
+	for _, index := range []uint16{0x0001, 0x0006, 0x0007} {
+		record := make([]byte, 28)
+		binary.BigEndian.PutUint16(record[0:], index)
+		copy(record[2:22], padRight(module.OrderNumber, 20, ' '))
+		if index != 0x0001 {
+			copy(record[24:], []byte{'V', 3, 3, 0})
+		}
+		identification = append(identification, record...)
+	}
+	server.SetSZL(szlModuleIdentification, 0, 28, identification)
+
+	// automation system name (0x0001), module name (0x0002) and serial number (0x0005)
+	var components []byte
+	for _, component := range []struct {
+		index uint16
+		name  string
+	}{{0x0001, "S7 simulator"}, {0x0002, module.ModuleName}, {0x0005, module.SerialNumber}} {
+		record := make([]byte, 34)
+		binary.BigEndian.PutUint16(record[0:], component.index)
+		copy(record[2:34], padRight(component.name, 32, 0))
+		components = append(components, record...)
+	}
+	server.SetSZL(szlComponentIdentification, 0, 34, components)
+
+	// RUN after STOP
+	mode := make([]byte, 20)
+	binary.BigEndian.PutUint16(mode[0:], 0x4303)
+	mode[3] = 0x48
+	server.SetSZL(szlOperatingMode, 0, 20, mode)
+}
+
+// padRight pads or truncates the text to the size
+func padRight(text string, size int, pad byte) []byte {
+	buffer := make([]byte, size)
+	for i := range buffer {
+		buffer[i] = pad
+	}
+	copy(buffer, text)
+	return buffer
+}
@@ -0,0 +1,67 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+)
+
+// closeS7Client closes the connection of a client
+func closeS7Client(s7Client *S7Client) {
+	if s7Client != nil && s7Client.Handler != nil {
+		_ = s7Client.Handler.Close()
+	}
+}
+
+// lockDevice releases the connection of a locked device and stops its background tasks and its connection probe,
+// the device is connected again when it is unlocked
+func (s *Driver) lockDevice(deviceName string) {
+	s.stopDeviceTasks(deviceName)
+	s.stopProbe(deviceName)
+
+	s.mu.Lock()
+	if s.locked == nil {
+		s.locked = make(map[string]bool)
+	}
+	s.locked[deviceName] = true
+	s7Client := s.s7Clients[deviceName]
+	delete(s.s7Clients, deviceName)
+	s.mu.Unlock()
+
+	closeS7Client(s7Client)
+	s.recordConnection(deviceName, false)
+	s.lc.Infof("Device %s is locked, its connection is released", deviceName)
+}
+
+// unlockDevice records that a device is unlocked, the caller connects it and starts its tasks
+func (s *Driver) unlockDevice(deviceName string) {
+	s.mu.Lock()
+	locked := s.locked[deviceName]
+	delete(s.locked, deviceName)
+	s.mu.Unlock()
+	if locked {
+		s.lc.Infof("Device %s is unlocked, connecting it", deviceName)
+	}
+}
+
+// isLocked returns true when the AdminState of the device is LOCKED
+func (s *Driver) isLocked(deviceName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked[deviceName]
+}
+
+// checkUnlocked returns an error for a locked device, the SDK refuses their commands but a command
+// may be sent while the device is being locked
+func (s *Driver) checkUnlocked(deviceName string) error {
+	if s.isLocked(deviceName) {
+		err := fmt.Errorf("device %s is locked", deviceName)
+		s.lc.Errorf(err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package driver
+
+import (
+	"testing"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestE2E_LockDevice(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	reqs := []sdkModel.CommandRequest{newTestRequest("word", "DB1.DBW0", common.ValueTypeUint16)}
+	readValues(t, s, protocols, reqs)
+	s7Client := s.s7Clients[testDevice]
+
+	if err := s.UpdateDevice(testDevice, protocols, models.Locked); err != nil {
+		t.Fatalf("UpdateDevice(LOCKED) error = %v", err)
+	}
+	if _, ok := s.s7Clients[testDevice]; ok || !s.isLocked(testDevice) {
+		t.Errorf("a locked device should release its client")
+	}
+	if _, ok := s.tasks[testDevice]; ok {
+		t.Errorf("a locked device should stop its tasks")
+	}
+	if err := s7Client.Client.AGReadDB(1, 0, 2, make([]byte, 2)); err == nil {
+		t.Errorf("the connection of a locked device should be closed")
+	}
+	requests := server.Requests()
+	if _, err := s.HandleReadCommands(testDevice, protocols, reqs); err == nil {
+		t.Errorf("HandleReadCommands() of a locked device should fail")
+	}
+	if server.Requests() != requests {
+		t.Errorf("a locked device should not be connected")
+	}
+
+	if err := s.UpdateDevice(testDevice, protocols, models.Unlocked); err != nil {
+		t.Fatalf("UpdateDevice(UNLOCKED) error = %v", err)
+	}
+	if s.isLocked(testDevice) || s.s7Clients[testDevice] == nil {
+		t.Errorf("an unlocked device should be connected")
+	}
+	readValues(t, s, protocols, reqs)
+}
+
+func TestE2E_UpdateDeviceKeepsConnection(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	if err := s.AddDevice(testDevice, protocols, models.Unlocked); err != nil {
+		t.Fatalf("AddDevice() error = %v", err)
+	}
+	s7Client := s.s7Clients[testDevice]
+	stopped := false
+	s.tasks[testDevice] = func() { stopped = true }
+
+	// an OperatingState change keeps the connection and the tasks
+	for range 3 {
+		if err := s.UpdateDevice(testDevice, protocols, models.Unlocked); err != nil {
+			t.Fatalf("UpdateDevice() error = %v", err)
+		}
+	}
+	if s.s7Clients[testDevice] != s7Client || stopped {
+		t.Errorf("an unchanged device should keep its client and tasks")
+	}
+	waitConnections(t, server, 1)
+
+	// a protocol change connects again and closes the previous connection
+	protocols[Protocol]["Timeout"] = "2"
+	if err := s.UpdateDevice(testDevice, protocols, models.Unlocked); err != nil {
+		t.Fatalf("UpdateDevice() error = %v", err)
+	}
+	if s.s7Clients[testDevice] == s7Client {
+		t.Errorf("a changed device should be connected again")
+	}
+	waitConnections(t, server, 1)
+
+	// a reconnect closes the failed connection
+	s.reconnect(testDevice, protocols)
+	waitConnections(t, server, 1)
+}
+
+// waitConnections waits until the server has n open connections
+func waitConnections(t *testing.T, server *s7server.Server, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for server.Connections() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d open connections, want %d", server.Connections(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,305 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/robinson/gos7"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+// State of the alarm events
+const (
+	alarmComing = "coming"
+	alarmGoing  = "going"
+)
+
+// AlarmDefinition is the message of an alarm bit
+type AlarmDefinition struct {
+	Text     string `yaml:"text"`
+	Severity string `yaml:"severity"`
+}
+
+// alarmState is the alarm definitions and the active alarms of an alarm resource
+type alarmState struct {
+	attributes  string // the attributes which the definitions are parsed from
+	words       int
+	definitions map[int]AlarmDefinition
+	active      map[int]time.Time // alarm bit to coming time
+}
+
+// newAlarmState parses the alarm words and definitions of the resource attributes,
+// 'Alarms' holds the definitions inline and 'AlarmFile' refers to a YAML or JSON file of them
+func newAlarmState(attributes map[string]any) (*alarmState, error) {
+	state := &alarmState{
+		attributes: fmt.Sprint(attributes),
+		words:      1,
+		active:     make(map[int]time.Time),
+	}
+	if value, ok := attributes[ALARM_WORDS]; ok {
+		words, err := cast.ToIntE(value)
+		if err != nil || words < 1 || words > 1024 {
+			return nil, fmt.Errorf("%s %v should be 1 to 1024", ALARM_WORDS, value)
+		}
+		state.words = words
+	}
+
+	definitions := attributes[ALARMS]
+	if path := cast.ToString(attributes[ALARM_FILE]); path != "" {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s %s failed, error: %v", ALARM_FILE, path, err)
+		}
+		var file map[string]any
+		if err = yaml.Unmarshal(contents, &file); err != nil {
+			return nil, fmt.Errorf("parse %s %s failed, error: %v", ALARM_FILE, path, err)
+		}
+		definitions = file
+	}
+	var err error
+	if state.definitions, err = parseAlarmDefinitions(definitions, state.words*16); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// parseAlarmDefinitions parses a map of alarm bits, like "12" or "1.4" (byte.bit), to a text or to a text and severity
+func parseAlarmDefinitions(value any, bits int) (map[int]AlarmDefinition, error) {
+	definitions := make(map[int]AlarmDefinition)
+	if value == nil {
+		return definitions, nil
+	}
+	entries, err := cast.ToStringMapE(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s should map the alarm bits to the messages, error: %v", ALARMS, err)
+	}
+	for key, entry := range entries {
+		bit, err := parseAlarmBit(key)
+		if err != nil || bit >= bits {
+			return nil, fmt.Errorf("alarm bit %s should be a bit number or byte.bit below %d", key, bits)
+		}
+		var definition AlarmDefinition
+		if text, ok := entry.(string); ok {
+			definition.Text = text
+		} else {
+			fields, err := cast.ToStringMapStringE(entry)
+			if err != nil {
+				return nil, fmt.Errorf("alarm %s should be a text or have a text and a severity", key)
+			}
+			definition.Text = fields["text"]
+			definition.Severity = fields["severity"]
+		}
+		definitions[bit] = definition
+	}
+	return definitions, nil
+}
+
+// parseAlarmBit parses an alarm bit number like "12", or a byte and bit like "1.4"
+func parseAlarmBit(key string) (int, error) {
+	if byteOffset, bit, found := strings.Cut(strings.TrimSpace(key), "."); found {
+		b, err := strconv.Atoi(byteOffset)
+		if err != nil || b < 0 {
+			return 0, fmt.Errorf("invalid alarm byte %s", byteOffset)
+		}
+		n, err := strconv.Atoi(bit)
+		if err != nil || n < 0 || n > 7 {
+			return 0, fmt.Errorf("invalid alarm bit %s", bit)
+		}
+		return b*8 + n, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(key))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid alarm bit %s", key)
+	}
+	return n, nil
+}
+
+// alarmChange is an alarm coming or going
+type alarmChange struct {
+	bit        int
+	state      string
+	comingTime time.Time
+}
+
+// update compares the alarm bits with the active alarms and returns the coming and going alarms in bit order
+func (a *alarmState) update(data []byte, now time.Time) (changes []alarmChange) {
+	for bit := 0; bit < len(data)*8; bit++ {
+		set := data[bit/8]&(1<<uint(bit%8)) != 0
+		comingTime, active := a.active[bit]
+		switch {
+		case set && !active:
+			a.active[bit] = now
+			changes = append(changes, alarmChange{bit: bit, state: alarmComing, comingTime: now})
+		case !set && active:
+			delete(a.active, bit)
+			changes = append(changes, alarmChange{bit: bit, state: alarmGoing, comingTime: comingTime})
+		}
+	}
+	return changes
+}
+
+// alarm returns the description of an alarm bit
+func (a *alarmState) alarm(resourceName string, dbInfo *DBInfo, bit int) map[string]any {
+	definition, ok := a.definitions[bit]
+	if !ok {
+		definition.Text = fmt.Sprintf("Alarm %d", bit)
+	}
+	start := dbInfo.Start
+	if dbInfo.WordLength == s7wlbit {
+		start = start >> 3
+	}
+	return map[string]any{
+		"resource": resourceName,
+		"bit":      bit,
+		"address":  fmt.Sprintf("DB%d.DBX%d.%d", dbInfo.DBNumber, start+bit/8, bit%8),
+		"text":     definition.Text,
+		"severity": definition.Severity,
+	}
+}
+
+// activeAlarms lists the active alarms in bit order with their coming time
+func (a *alarmState) activeAlarms(resourceName string, dbInfo *DBInfo) []any {
+	bits := make([]int, 0, len(a.active))
+	for bit := range a.active {
+		bits = append(bits, bit)
+	}
+	sort.Ints(bits)
+	alarms := make([]any, 0, len(bits))
+	for _, bit := range bits {
+		alarm := a.alarm(resourceName, dbInfo, bit)
+		alarm["comingTime"] = a.active[bit].UTC().Format(time.RFC3339Nano)
+		alarms = append(alarms, alarm)
+	}
+	return alarms
+}
+
+// getAlarmState returns the alarm state of the device resource, it is recreated when the attributes change
+func (s *Driver) getAlarmState(deviceName string, req sdkModel.CommandRequest) (*alarmState, error) {
+	key := deviceName + "/" + req.DeviceResourceName
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.alarms[key]
+	if ok && state.attributes == fmt.Sprint(req.Attributes) {
+		return state, nil
+	}
+	state, err := newAlarmState(req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	if s.alarms == nil {
+		s.alarms = make(map[string]*alarmState)
+	}
+	s.alarms[key] = state
+	return state, nil
+}
+
+// readAlarmCommands reads the alarm words, emits an async event per coming and going alarm
+// and returns the active alarms of each resource
+func (s *Driver) readAlarmCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []sdkModel.CommandRequest) (res []*sdkModel.CommandValue) {
+	family, err := getFamily(protocols[Protocol])
+	if err != nil {
+		s.lc.Errorf("read alarms %+v failed, error: %v", reqs, err)
+		return nil
+	}
+	for _, req := range reqs {
+		state, err := s.getAlarmState(deviceName, req)
+		if err != nil {
+			s.lc.Errorf("invalid alarms of resource %s, error: %v", req.DeviceResourceName, err)
+			continue
+		}
+		dbInfo, err := s.getFamilyDBInfo(deviceName, family, cast.ToString(req.Attributes["NodeName"]))
+		if err != nil {
+			continue
+		}
+		if dbInfo.Area != 0x84 || (dbInfo.WordLength == s7wlbit && dbInfo.Start&0x07 != 0) {
+			s.lc.Errorf("alarm words of resource %s should start at a byte of a DB", req.DeviceResourceName)
+			continue
+		}
+		start := dbInfo.Start
+		if dbInfo.WordLength == s7wlbit {
+			start = start >> 3
+		}
+
+		data := make([]byte, state.words*2)
+		if err = s.readAlarmWords(deviceName, protocols, dbInfo.DBNumber, start, data); err != nil {
+			s.lc.Errorf("read alarm words of resource %s failed, error: %v", req.DeviceResourceName, err)
+			continue
+		}
+
+		now := time.Now()
+		s.mu.Lock()
+		changes := state.update(data, now)
+		active := state.activeAlarms(req.DeviceResourceName, dbInfo)
+		s.mu.Unlock()
+		for _, change := range changes {
+			s.sendAlarmEvent(deviceName, state, req.DeviceResourceName, dbInfo, change, now)
+		}
+
+		result, err := sdkModel.NewCommandValue(req.DeviceResourceName, common.ValueTypeObject, map[string]any{"activeAlarms": active})
+		if err != nil {
+			s.lc.Errorf("getCommandValue error: %v", err)
+			continue
+		}
+		result.Origin = now.UnixNano()
+		res = append(res, result)
+	}
+	return res
+}
+
+// readAlarmWords reads the alarm words of a DB with readBatch, in ranges fitting the PDU length,
+// so the alarm reads are retried, counted in the metrics and recorded in the connection state like the other reads
+func (s *Driver) readAlarmWords(deviceName string, protocols map[string]models.ProtocolProperties, dbNumber int, start int, data []byte) error {
+	pduLength := getPDULength(s.getS7Client(deviceName, protocols))
+	maxRangeSize := (pduLength - readReplyHeader - readReplyItemHeader) &^ 1
+	var items []gos7.S7DataItem
+	for offset := 0; offset < len(data); offset += maxRangeSize {
+		end := min(offset+maxRangeSize, len(data))
+		items = append(items, gos7.S7DataItem{
+			Area:     0x84,
+			WordLen:  s7wlbyte,
+			DBNumber: dbNumber,
+			Start:    start + offset,
+			Amount:   end - offset,
+			Data:     data[offset:end],
+		})
+	}
+	for _, batch := range batchItems(items, pduLength, s.getConfig().batchSize) {
+		if err := s.readBatch(deviceName, protocols, batch); err != nil {
+			return err
+		}
+		for _, item := range batch {
+			if item.Error != "" {
+				return fmt.Errorf("%s", item.Error)
+			}
+		}
+	}
+	return nil
+}
+
+// sendAlarmEvent emits the coming or going of an alarm to the alarm resource
+func (s *Driver) sendAlarmEvent(deviceName string, state *alarmState, resourceName string, dbInfo *DBInfo, change alarmChange, now time.Time) {
+	alarm := state.alarm(resourceName, dbInfo, change.bit)
+	alarm["state"] = change.state
+	alarm["time"] = now.UTC().Format(time.RFC3339Nano)
+	alarm["comingTime"] = change.comingTime.UTC().Format(time.RFC3339Nano)
+	if change.state == alarmGoing {
+		alarm["duration"] = now.Sub(change.comingTime).Milliseconds()
+	}
+	s.lc.Infof("Alarm %s of device %s: %v", change.state, deviceName, alarm)
+	s.sendAsyncValue(s.deviceContext(deviceName), deviceName, ALARM_RESOURCE, common.ValueTypeObject, alarm)
+}
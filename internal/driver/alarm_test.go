@@ -0,0 +1,160 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestNewAlarmState(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "alarms.yaml")
+	contents := "\"0.1\": {text: Motor overload, severity: major}\n\"17\": Door open\n"
+	if err := os.WriteFile(file, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		want       map[int]AlarmDefinition
+		wantErr    bool
+	}{
+		{
+			name: "inline",
+			attributes: map[string]any{ALARM_WORDS: 2, ALARMS: map[string]any{
+				"0":   map[string]any{"text": "Emergency stop", "severity": "critical"},
+				"3.7": "Low pressure",
+			}},
+			want: map[int]AlarmDefinition{0: {Text: "Emergency stop", Severity: "critical"}, 31: {Text: "Low pressure"}},
+		},
+		{
+			name:       "file",
+			attributes: map[string]any{ALARM_WORDS: 2, ALARM_FILE: file},
+			want:       map[int]AlarmDefinition{1: {Text: "Motor overload", Severity: "major"}, 17: {Text: "Door open"}},
+		},
+		{name: "bit out of the words", attributes: map[string]any{ALARM_WORDS: 1, ALARMS: map[string]any{"16": "Too far"}}, wantErr: true},
+		{name: "invalid bit", attributes: map[string]any{ALARM_WORDS: 1, ALARMS: map[string]any{"1.8": "No such bit"}}, wantErr: true},
+		{name: "invalid words", attributes: map[string]any{ALARM_WORDS: 0}, wantErr: true},
+		{name: "missing file", attributes: map[string]any{ALARM_WORDS: 1, ALARM_FILE: filepath.Join(t.TempDir(), "none.yaml")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := newAlarmState(tt.attributes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newAlarmState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(state.definitions) != len(tt.want) {
+				t.Fatalf("definitions = %v, want %v", state.definitions, tt.want)
+			}
+			for bit, definition := range tt.want {
+				if state.definitions[bit] != definition {
+					t.Errorf("definition of bit %d = %v, want %v", bit, state.definitions[bit], definition)
+				}
+			}
+		})
+	}
+}
+
+func TestAlarmStateUpdate(t *testing.T) {
+	state, _ := newAlarmState(map[string]any{ALARM_WORDS: 1})
+	start := time.Now()
+
+	changes := state.update([]byte{0x01, 0x80}, start)
+	if len(changes) != 2 || changes[0].bit != 0 || changes[1].bit != 15 || changes[0].state != alarmComing {
+		t.Errorf("first update = %+v, want bits 0 and 15 coming", changes)
+	}
+	if changes = state.update([]byte{0x01, 0x80}, start.Add(time.Second)); len(changes) != 0 {
+		t.Errorf("unchanged update = %+v", changes)
+	}
+	changes = state.update([]byte{0x00, 0x80}, start.Add(2*time.Second))
+	if len(changes) != 1 || changes[0].bit != 0 || changes[0].state != alarmGoing || !changes[0].comingTime.Equal(start) {
+		t.Errorf("going update = %+v, want bit 0 going", changes)
+	}
+}
+
+func TestReadAlarms(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	asyncCh := make(chan *sdkModel.AsyncValues, 16)
+	s.asyncCh = asyncCh
+
+	req := sdkModel.CommandRequest{
+		DeviceResourceName: "alarms",
+		Type:               common.ValueTypeObject,
+		Attributes: map[string]any{
+			"NodeName":  "DB1.DBW10",
+			ALARM_WORDS: 2,
+			ALARMS:      map[string]any{"1.2": map[string]any{"text": "Motor overload", "severity": "major"}},
+		},
+	}
+	read := func() []any {
+		values := readValues(t, s, protocols, []sdkModel.CommandRequest{req})
+		return values["alarms"].(map[string]any)["activeAlarms"].([]any)
+	}
+
+	if active := read(); len(active) != 0 || len(asyncCh) != 0 {
+		t.Errorf("active alarms = %v, want none", active)
+	}
+
+	_ = server.WriteBit(s7server.AreaDB, 1, 11, 2, true)
+	active := read()
+	if len(active) != 1 || active[0].(map[string]any)["text"] != "Motor overload" || active[0].(map[string]any)["address"] != "DB1.DBX11.2" {
+		t.Errorf("active alarms = %v, want the motor overload", active)
+	}
+	if len(asyncCh) != 1 {
+		t.Fatalf("%d alarm events, want the coming one", len(asyncCh))
+	}
+	event := (<-asyncCh).CommandValues[0].Value.(map[string]any)
+	if event["state"] != alarmComing || event["severity"] != "major" || event["bit"] != 10 {
+		t.Errorf("coming event = %v", event)
+	}
+
+	_ = server.WriteBit(s7server.AreaDB, 1, 11, 2, false)
+	if active = read(); len(active) != 0 {
+		t.Errorf("active alarms = %v, want none", active)
+	}
+	if event = (<-asyncCh).CommandValues[0].Value.(map[string]any); event["state"] != alarmGoing {
+		t.Errorf("going event = %v", event)
+	}
+}
+
+func TestReadAlarmsThroughReadBatch(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	s.asyncCh = make(chan *sdkModel.AsyncValues, 16)
+	server.SetDB(2, make([]byte, 1024))
+	req := sdkModel.CommandRequest{
+		DeviceResourceName: "alarms",
+		Type:               common.ValueTypeObject,
+		Attributes:         map[string]any{"NodeName": "DB2.DBW0", ALARM_WORDS: 500},
+	}
+
+	// the alarm words exceed the PDU and are read in several ranges
+	_ = server.WriteBit(s7server.AreaDB, 2, 999, 7, true)
+	values := readValues(t, s, protocols, []sdkModel.CommandRequest{req})
+	active := values["alarms"].(map[string]any)["activeAlarms"].([]any)
+	if len(active) != 1 || active[0].(map[string]any)["address"] != "DB2.DBX999.7" {
+		t.Errorf("active alarms = %v, want DB2.DBX999.7", active)
+	}
+	m := s.getDeviceMetrics(testDevice)
+	if m.readRequests.Count() == 0 || m.bytesRead.Count() != 1000 {
+		t.Errorf("read metrics = %d requests, %d bytes, want the alarm words", m.readRequests.Count(), m.bytesRead.Count())
+	}
+
+	// a failed read is retried with a reconnection and counted as a request error
+	server.DropNextRequests(1)
+	values = readValues(t, s, protocols, []sdkModel.CommandRequest{req})
+	if _, ok := values["alarms"]; !ok {
+		t.Errorf("alarms are not read after a retry")
+	}
+	if m.requestErrors.Count() != 1 || m.reconnects.Count() != 1 {
+		t.Errorf("retry metrics = %d errors, %d reconnects, want 1", m.requestErrors.Count(), m.reconnects.Count())
+	}
+}
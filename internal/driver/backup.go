@@ -0,0 +1,368 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+// Default directory of the block backup archives
+const defaultBackupDir = "./backup"
+
+// Start upload request, the file name is "_0" + block type + 5 digits block number + "A" (active file system)
+var startUploadTelegram = []byte{
+	3, 0, 0, 35, 2, 240, 128, 50, 1, 0, 0, 0, 0, 0, 18, 0, 0,
+	0x1D, 0, 0, 0, 0, 0, 0, 0, 9,
+	'_', '0',
+	0,             // Block type (idx=28)
+	0, 0, 0, 0, 0, // ASCII block number
+	'A'}
+
+// Upload request, the upload ID is at byte 24
+var uploadTelegram = []byte{
+	3, 0, 0, 25, 2, 240, 128, 50, 1, 0, 0, 0, 0, 0, 8, 0, 0,
+	0x1E, 0, 0, 0, 0, 0, 0,
+	0} // Upload ID
+
+// End upload request, the upload ID is at byte 24
+var endUploadTelegram = []byte{
+	3, 0, 0, 25, 2, 240, 128, 50, 1, 0, 0, 0, 0, 0, 8, 0, 0,
+	0x1F, 0, 0, 0, 0, 0, 0,
+	0} // Upload ID
+
+var blockTypes = map[string]int{
+	"OB": blockOB,
+	"DB": blockDB,
+	"FC": blockFC,
+	"FB": blockFB,
+}
+
+// BackupEntry is a block in the manifest of a backup archive
+type BackupEntry struct {
+	File     string `json:"file"`
+	Type     string `json:"type"`
+	Number   int    `json:"number"`
+	Size     int    `json:"size"`
+	CheckSum string `json:"checksum"`
+	SHA256   string `json:"sha256"`
+	CodeDate string `json:"codeDate"`
+}
+
+// BackupManifest lists the blocks of a backup archive
+type BackupManifest struct {
+	Device    string        `json:"device"`
+	Timestamp string        `json:"timestamp"`
+	Archive   string        `json:"archive"`
+	Blocks    []BackupEntry `json:"blocks"`
+}
+
+// backupResult is the manifest of the last backup of a device, or the error of the failed backup
+type backupResult struct {
+	manifest *BackupManifest
+	err      error
+}
+
+// UploadBlock uploads a block from the PLC, the upload session is ended even when the upload fails
+// since the PLC has only a few of them
+func (c *S7Client) UploadBlock(blockType int, number int) (block []byte, err error) {
+	if c == nil || c.Handler == nil {
+		return nil, fmt.Errorf("S7 client is not connected")
+	}
+	if number < 0 || number > 99999 {
+		return nil, fmt.Errorf("block number %d is out of range", number)
+	}
+
+	request := make([]byte, len(startUploadTelegram))
+	copy(request, startUploadTelegram)
+	request[28] = byte(blockType)
+	copy(request[29:34], fmt.Sprintf("%05d", number))
+	params, _, err := c.sendJob(request, 0x1D)
+	if err != nil {
+		return nil, fmt.Errorf("start upload of %s%d failed, error: %v", blockTypeNames[blockType], number, err)
+	}
+	if len(params) < 8 {
+		return nil, fmt.Errorf("invalid start upload response of %s%d", blockTypeNames[blockType], number)
+	}
+	uploadId := params[7]
+	defer func() {
+		request := make([]byte, len(endUploadTelegram))
+		copy(request, endUploadTelegram)
+		request[24] = uploadId
+		if _, _, endErr := c.sendJob(request, 0x1F); endErr != nil && err == nil {
+			block, err = nil, fmt.Errorf("end upload of %s%d failed, error: %v", blockTypeNames[blockType], number, endErr)
+		}
+	}()
+
+	for {
+		request = make([]byte, len(uploadTelegram))
+		copy(request, uploadTelegram)
+		request[24] = uploadId
+		params, data, err := c.sendJob(request, 0x1E)
+		if err != nil {
+			return nil, fmt.Errorf("upload of %s%d failed, error: %v", blockTypeNames[blockType], number, err)
+		}
+		// data length and 0x00FB precede the block data
+		if len(data) < 4 {
+			return nil, fmt.Errorf("invalid upload response of %s%d", blockTypeNames[blockType], number)
+		}
+		size := int(binary.BigEndian.Uint16(data[0:]))
+		if size > len(data)-4 {
+			size = len(data) - 4
+		}
+		block = append(block, data[4:4+size]...)
+		// function status 0x01 means more data follows
+		if len(params) < 2 || params[1] != 0x01 {
+			return block, nil
+		}
+	}
+}
+
+// sendJob sends a job request and returns the parameters and data of the acknowledgement
+func (c *S7Client) sendJob(request []byte, function byte) (params []byte, data []byte, err error) {
+	response, err := c.Handler.Send(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(response) < 19 || response[7] != 0x32 || response[8] != 0x03 {
+		return nil, nil, fmt.Errorf("invalid response to function 0x%02X", function)
+	}
+	if response[17] != 0 || response[18] != 0 {
+		return nil, nil, fmt.Errorf("function 0x%02X failed, error class: 0x%02X, error code: 0x%02X", function, response[17], response[18])
+	}
+	parLength := int(binary.BigEndian.Uint16(response[13:]))
+	dataLength := int(binary.BigEndian.Uint16(response[15:]))
+	if len(response) < 19+parLength+dataLength {
+		return nil, nil, fmt.Errorf("invalid response size %d to function 0x%02X", len(response), function)
+	}
+	params = response[19 : 19+parLength]
+	data = response[19+parLength : 19+parLength+dataLength]
+	if len(params) == 0 || params[0] != function {
+		return nil, nil, fmt.Errorf("unexpected response to function 0x%02X", function)
+	}
+	return params, data, nil
+}
+
+// selectBlocks filters the blocks by a selection like "ALL", "DB" or "OB1,FC2,DB10"
+func selectBlocks(blocks map[string]BlockInfo, selection string) ([]BlockInfo, error) {
+	selection = strings.ToUpper(strings.ReplaceAll(selection, " ", ""))
+	if selection == "" {
+		selection = "ALL"
+	}
+
+	selected := map[string]bool{}
+	for _, item := range strings.Split(selection, ",") {
+		switch {
+		case item == "ALL":
+			for name := range blocks {
+				selected[name] = true
+			}
+		case len(item) >= 2 && blockTypes[item[0:2]] != 0:
+			if len(item) == 2 {
+				for name, block := range blocks {
+					if block.Type == item {
+						selected[name] = true
+					}
+				}
+				continue
+			}
+			number, err := strconv.Atoi(item[2:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid block %s in selection %s", item, selection)
+			}
+			name := fmt.Sprintf("%s%d", item[0:2], number)
+			if _, ok := blocks[name]; !ok {
+				return nil, fmt.Errorf("block %s is not found in the PLC", name)
+			}
+			selected[name] = true
+		default:
+			return nil, fmt.Errorf("invalid block %s in selection %s", item, selection)
+		}
+	}
+
+	result := make([]BlockInfo, 0, len(selected))
+	for _, name := range sortedBlockNames(blocks) {
+		if selected[name] {
+			result = append(result, blocks[name])
+		}
+	}
+	return result, nil
+}
+
+// backupBlocks uploads the selected blocks of the device into a timestamped archive,
+// the result is kept for the reads of the backup resources
+func (s *Driver) backupBlocks(deviceName string, protocols map[string]models.ProtocolProperties, selection string) (manifest *BackupManifest, err error) {
+	defer func() {
+		s.mu.Lock()
+		if s.backups == nil {
+			s.backups = make(map[string]*backupResult)
+		}
+		s.backups[deviceName] = &backupResult{manifest: manifest, err: err}
+		s.mu.Unlock()
+	}()
+
+	s7Client := s.getS7Client(deviceName, protocols)
+	blocks, err := s7Client.listBlocks()
+	if err != nil {
+		if isTransportError(err) {
+			s.dropS7Client(deviceName)
+		}
+		return nil, fmt.Errorf("list blocks of device %s failed, error: %v", deviceName, err)
+	}
+	selected, err := selectBlocks(blocks, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().UTC()
+	dir := filepath.Join(s.backupDir, deviceName)
+	if err = os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("create backup directory %s failed, error: %v", dir, err)
+	}
+	manifest = &BackupManifest{
+		Device:    deviceName,
+		Timestamp: timestamp.Format(time.RFC3339),
+		Archive:   filepath.Join(dir, fmt.Sprintf("%s-%s.tar.gz", deviceName, timestamp.Format("20060102T150405Z"))),
+		Blocks:    []BackupEntry{},
+	}
+
+	files := map[string][]byte{}
+	for _, block := range selected {
+		data, err := s7Client.UploadBlock(blockTypes[block.Type], block.Number)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		entry := BackupEntry{
+			File:     block.Name() + ".mc7",
+			Type:     block.Type,
+			Number:   block.Number,
+			Size:     len(data),
+			CheckSum: fmt.Sprintf("0x%04X", block.CheckSum),
+			SHA256:   hex.EncodeToString(sum[:]),
+			CodeDate: block.CodeDate,
+		}
+		files[entry.File] = data
+		manifest.Blocks = append(manifest.Blocks, entry)
+	}
+
+	if err = writeBackupArchive(manifest, files); err != nil {
+		return nil, err
+	}
+	s.lc.Infof("Backup of %d blocks of device %s is stored in %s", len(manifest.Blocks), deviceName, manifest.Archive)
+	return manifest, nil
+}
+
+// writeBackupArchive writes the manifest and the blocks into a tar.gz archive
+func writeBackupArchive(manifest *BackupManifest, files map[string][]byte) (err error) {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(manifest.Archive)
+	if err != nil {
+		return fmt.Errorf("create backup archive %s failed, error: %v", manifest.Archive, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	modTime, _ := time.Parse(time.RFC3339, manifest.Timestamp)
+	write := func(name string, data []byte) error {
+		header := &tar.Header{Name: name, Mode: 0640, Size: int64(len(data)), ModTime: modTime}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err = write("manifest.json", content); err != nil {
+		return err
+	}
+	for _, entry := range manifest.Blocks {
+		if err = write(entry.File, files[entry.File]); err != nil {
+			return err
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// backupCommandValue returns the manifest as Object or the archive path as String
+func backupCommandValue(req sdkModel.CommandRequest, manifest *BackupManifest) (*sdkModel.CommandValue, error) {
+	var value any
+	switch req.Type {
+	case common.ValueTypeString:
+		value = manifest.Archive
+	case common.ValueTypeObject:
+		content, err := json.Marshal(manifest)
+		if err != nil {
+			return nil, err
+		}
+		var object map[string]any
+		if err = json.Unmarshal(content, &object); err != nil {
+			return nil, err
+		}
+		value = object
+	default:
+		return nil, fmt.Errorf("block backup resource %s should be String or Object, not %s", req.DeviceResourceName, req.Type)
+	}
+
+	result, err := sdkModel.NewCommandValue(req.DeviceResourceName, req.Type, value)
+	if err != nil {
+		return nil, err
+	}
+	result.Origin = time.Now().UnixNano()
+	return result, nil
+}
+
+// readBackupCommands returns the manifest of the last backup of the device, the backups run only on the writes
+// since an upload is slow and takes an upload session of the PLC. The read fails when the last backup failed.
+func (s *Driver) readBackupCommands(deviceName string, reqs []sdkModel.CommandRequest) (res []*sdkModel.CommandValue, err error) {
+	s.mu.Lock()
+	backup := s.backups[deviceName]
+	s.mu.Unlock()
+	if backup == nil {
+		s.lc.Errorf("No block backup of device %s, write a block selection to back up the blocks", deviceName)
+		return nil, fmt.Errorf("no block backup of device %s, write a block selection to back up the blocks", deviceName)
+	}
+	if backup.err != nil {
+		s.lc.Errorf("Last block backup of device %s failed, error: %v", deviceName, backup.err)
+		return nil, fmt.Errorf("last block backup of device %s failed, error: %w", deviceName, backup.err)
+	}
+	for _, req := range reqs {
+		result, err := backupCommandValue(req, backup.manifest)
+		if err != nil {
+			s.lc.Errorf("backupCommandValue error: %v", err)
+			return nil, err
+		}
+		res = append(res, result)
+	}
+	return res, nil
+}
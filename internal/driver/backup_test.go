@@ -0,0 +1,198 @@
+package driver
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestSelectBlocks(t *testing.T) {
+	blocks := map[string]BlockInfo{}
+	for _, block := range []BlockInfo{
+		{Type: "OB", Number: 1},
+		{Type: "FC", Number: 2},
+		{Type: "DB", Number: 1},
+		{Type: "DB", Number: 10},
+	} {
+		blocks[block.Name()] = block
+	}
+
+	tests := []struct {
+		name      string
+		selection string
+		want      []string
+		wantErr   bool
+	}{
+		{name: "empty selection", selection: "", want: []string{"DB1", "DB10", "FC2", "OB1"}},
+		{name: "all blocks", selection: "all", want: []string{"DB1", "DB10", "FC2", "OB1"}},
+		{name: "block type", selection: "DB", want: []string{"DB1", "DB10"}},
+		{name: "block list", selection: "OB1, FC2, DB10", want: []string{"DB10", "FC2", "OB1"}},
+		{name: "unknown block", selection: "FB1", wantErr: true},
+		{name: "invalid block", selection: "DBX", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := selectBlocks(blocks, tt.selection)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectBlocks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var got []string
+			for _, block := range selected {
+				got = append(got, block.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectBlocks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// readBackupArchive returns the files of a backup archive
+func readBackupArchive(t *testing.T, archive string) map[string][]byte {
+	t.Helper()
+	file, err := os.Open(archive)
+	if err != nil {
+		t.Fatalf("open backup archive error = %v", err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatalf("read backup archive error = %v", err)
+		}
+		if files[header.Name], err = io.ReadAll(tr); err != nil {
+			t.Fatalf("read %s error = %v", header.Name, err)
+		}
+	}
+}
+
+func TestE2E_BackupBlocks(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	s.backupDir = t.TempDir()
+	ob1 := make([]byte, 1000) // uploaded in several parts
+	for i := range ob1 {
+		ob1[i] = byte(i)
+	}
+	db1 := []byte{0x01, 0x02, 0x03, 0x04}
+	server.SetBlock(s7server.BlockOB, 1, s7server.Block{Data: ob1, CheckSum: 0x1234})
+	server.SetBlock(s7server.BlockDB, 1, s7server.Block{Data: db1, CheckSum: 0x5678})
+	server.SetBlock(s7server.BlockFC, 2, s7server.Block{Data: []byte{0xFF}})
+
+	manifest, err := s.backupBlocks(testDevice, protocols, "OB1,DB")
+	if err != nil {
+		t.Fatalf("backupBlocks() error = %v", err)
+	}
+	if server.OpenUploads() != 0 {
+		t.Errorf("%d upload sessions are not ended", server.OpenUploads())
+	}
+	if len(manifest.Blocks) != 2 || manifest.Blocks[0].File != "DB1.mc7" || manifest.Blocks[1].File != "OB1.mc7" ||
+		manifest.Blocks[1].CheckSum != "0x1234" || manifest.Blocks[1].Size != len(ob1) {
+		t.Fatalf("backupBlocks() blocks = %+v, want DB1 and OB1", manifest.Blocks)
+	}
+
+	files := readBackupArchive(t, manifest.Archive)
+	var archived BackupManifest
+	if err = json.Unmarshal(files["manifest.json"], &archived); err != nil || !reflect.DeepEqual(&archived, manifest) {
+		t.Errorf("archived manifest = %+v, %v, want %+v", archived, err, manifest)
+	}
+	for name, want := range map[string][]byte{"OB1.mc7": ob1, "DB1.mc7": db1} {
+		if !bytes.Equal(files[name], want) {
+			t.Errorf("archived %s = % X, want % X", name, files[name], want)
+		}
+	}
+	sum := sha256.Sum256(ob1)
+	if manifest.Blocks[1].SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("OB1 sha256 = %s, want %x", manifest.Blocks[1].SHA256, sum)
+	}
+}
+
+func TestE2E_BackupFailedUpload(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	s.backupDir = t.TempDir()
+	server.SetBlock(s7server.BlockOB, 1, s7server.Block{Data: make([]byte, 100)})
+
+	server.SetFunctionError(0x1E, 0xD2, 0x05)
+	if _, err := s.backupBlocks(testDevice, protocols, "ALL"); err == nil {
+		t.Fatalf("backupBlocks() with a failed upload should fail")
+	}
+	if server.OpenUploads() != 0 {
+		t.Errorf("%d upload sessions are not ended after the failed upload", server.OpenUploads())
+	}
+
+	server.ClearFaults()
+	if _, err := s.backupBlocks(testDevice, protocols, "ALL"); err != nil {
+		t.Errorf("backupBlocks() after the failed upload error = %v", err)
+	}
+}
+
+func TestE2E_BackupResource(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	s.backupDir = t.TempDir()
+	server.SetBlock(s7server.BlockOB, 1, s7server.Block{Data: make([]byte, 100)})
+	server.SetBlock(s7server.BlockDB, 1, s7server.Block{Data: []byte{0x01, 0x02}})
+	req := newTestRequest("program-backup", "", common.ValueTypeObject)
+	req.Attributes[BLOCK_BACKUP] = "DB"
+	reqs := []sdkModel.CommandRequest{req}
+
+	// the reads never start a backup
+	requests := server.Requests()
+	if _, err := s.HandleReadCommands(testDevice, protocols, reqs); err == nil {
+		t.Errorf("HandleReadCommands() before the first backup should fail")
+	}
+	if server.Requests() != requests {
+		t.Errorf("HandleReadCommands() sent %d requests to the PLC", server.Requests()-requests)
+	}
+
+	// an empty selection backs up the blocks of the attribute
+	param, _ := sdkModel.NewCommandValue(req.DeviceResourceName, common.ValueTypeString, "")
+	if err := s.HandleWriteCommands(testDevice, protocols, reqs, []*sdkModel.CommandValue{param}); err != nil {
+		t.Fatalf("HandleWriteCommands() error = %v", err)
+	}
+	requests = server.Requests()
+	res, err := s.HandleReadCommands(testDevice, protocols, reqs)
+	if err != nil || len(res) != 1 {
+		t.Fatalf("HandleReadCommands() = %v, %v", res, err)
+	}
+	if server.Requests() != requests {
+		t.Errorf("HandleReadCommands() sent %d requests to the PLC", server.Requests()-requests)
+	}
+	manifest, _ := res[0].ObjectValue()
+	blocks, _ := manifest.(map[string]any)["blocks"].([]any)
+	if len(blocks) != 1 || blocks[0].(map[string]any)["file"] != "DB1.mc7" {
+		t.Errorf("read manifest = %v, want DB1", manifest)
+	}
+
+	// a failed backup fails the reads until the next backup
+	server.SetFunctionError(0x1E, 0xD2, 0x05)
+	param, _ = sdkModel.NewCommandValue(req.DeviceResourceName, common.ValueTypeString, "ALL")
+	if err = s.HandleWriteCommands(testDevice, protocols, reqs, []*sdkModel.CommandValue{param}); err == nil {
+		t.Fatalf("HandleWriteCommands() with a failed upload should fail")
+	}
+	if _, err = s.HandleReadCommands(testDevice, protocols, reqs); err == nil {
+		t.Errorf("HandleReadCommands() after a failed backup should fail")
+	}
+}
@@ -0,0 +1,181 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+
+	"github.com/spf13/cast"
+)
+
+// Encodings of the 'Encoding' attribute
+const (
+	encodingBCD       = "BCD"       // 2, 4 or 8 digits in a byte, word or double word
+	encodingSignedBCD = "SIGNEDBCD" // the sign nibble, 0x0 or 0xF, followed by 3 or 7 digits, like BCD_I and BCD_DI
+	encodingS5Time    = "S5TIME"    // 2 bits time base and 3 digits in a word, read and written in milliseconds
+)
+
+// time bases of S5TIME in milliseconds
+var s5TimeBases = []int64{10, 100, 1000, 10000}
+
+// getEncoding returns the encoding of the resource attributes, or empty for resources without 'Encoding'
+func getEncoding(attributes map[string]any, valueType string) (string, error) {
+	value, ok := attributes[ENCODING]
+	if !ok {
+		return "", nil
+	}
+	encoding := strings.ToUpper(cast.ToString(value))
+	switch encoding {
+	case encodingBCD, encodingSignedBCD, encodingS5Time:
+	default:
+		return "", fmt.Errorf("%s %v should be BCD, SignedBCD or S5TIME", ENCODING, value)
+	}
+	if encoding == encodingS5Time {
+		// the milliseconds, up to 9990000, overflow the 8 and 16-bit integers
+		switch valueType {
+		case common.ValueTypeUint32, common.ValueTypeInt32, common.ValueTypeUint64, common.ValueTypeInt64,
+			common.ValueTypeFloat32, common.ValueTypeFloat64:
+		default:
+			return "", fmt.Errorf("%s is supported by 32 and 64-bit integer and float resources only, not %s", encodingS5Time, valueType)
+		}
+		return encoding, nil
+	}
+	switch valueType {
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32,
+		common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32:
+	default:
+		return "", fmt.Errorf("%s is supported by 8, 16 and 32-bit integer resources only, not %s", ENCODING, valueType)
+	}
+	return encoding, nil
+}
+
+// decodeValue decodes the big-endian bytes of an encoded value to an integer
+func decodeValue(encoding string, buffer []byte) (int64, error) {
+	var raw uint64
+	for _, b := range buffer {
+		raw = raw<<8 | uint64(b)
+	}
+	digits := len(buffer) * 2
+	switch encoding {
+	case encodingBCD:
+		return decodeBCD(raw, digits)
+	case encodingSignedBCD:
+		if digits < 4 {
+			return 0, fmt.Errorf("signed BCD should be a word or a double word")
+		}
+		value, err := decodeBCD(raw, digits-1)
+		if err != nil {
+			return 0, err
+		}
+		switch sign := raw >> (4 * (digits - 1)); sign {
+		case 0x0:
+			return value, nil
+		case 0xF:
+			return -value, nil
+		default:
+			return 0, fmt.Errorf("invalid sign nibble %X of signed BCD", sign)
+		}
+	case encodingS5Time:
+		if digits != 4 {
+			return 0, fmt.Errorf("S5TIME should be a word")
+		}
+		value, err := decodeBCD(raw, 3)
+		if err != nil {
+			return 0, err
+		}
+		return value * s5TimeBases[raw>>12&0x03], nil
+	}
+	return 0, fmt.Errorf("unknown encoding %s", encoding)
+}
+
+// encodeValue encodes an integer to a value of the size in bytes, for helper.SetValueAt
+func encodeValue(encoding string, value int64, size int) (any, error) {
+	digits := size * 2
+	var raw uint64
+	var err error
+	switch encoding {
+	case encodingBCD:
+		raw, err = encodeBCD(value, digits)
+	case encodingSignedBCD:
+		if digits < 4 {
+			return nil, fmt.Errorf("signed BCD should be a word or a double word")
+		}
+		var sign uint64
+		if value < 0 {
+			sign, value = 0xF, -value
+		}
+		raw, err = encodeBCD(value, digits-1)
+		raw |= sign << (4 * (digits - 1))
+	case encodingS5Time:
+		if digits != 4 {
+			return nil, fmt.Errorf("S5TIME should be a word")
+		}
+		raw, err = encodeS5Time(value)
+	default:
+		err = fmt.Errorf("unknown encoding %s", encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch size {
+	case 1:
+		return uint8(raw), nil
+	case 2:
+		return uint16(raw), nil
+	default:
+		return uint32(raw), nil
+	}
+}
+
+// decodeBCD decodes the lowest digits of raw, one decimal digit per nibble
+func decodeBCD(raw uint64, digits int) (int64, error) {
+	var value int64
+	for i := digits - 1; i >= 0; i-- {
+		digit := raw >> (4 * i) & 0x0F
+		if digit > 9 {
+			return 0, fmt.Errorf("invalid BCD digit %X in %X", digit, raw)
+		}
+		value = value*10 + int64(digit)
+	}
+	return value, nil
+}
+
+// encodeBCD encodes a positive value in digits nibbles
+func encodeBCD(value int64, digits int) (uint64, error) {
+	if value < 0 {
+		return 0, fmt.Errorf("BCD value %d should not be negative", value)
+	}
+	var raw uint64
+	for i := 0; i < digits; i++ {
+		raw |= uint64(value%10) << (4 * i)
+		value /= 10
+	}
+	if value != 0 {
+		return 0, fmt.Errorf("BCD value should have at most %d digits", digits)
+	}
+	return raw, nil
+}
+
+// encodeS5Time encodes milliseconds with the smallest time base holding them in 3 digits,
+// the milliseconds below the time base are rounded
+func encodeS5Time(ms int64) (uint64, error) {
+	if ms < 0 {
+		return 0, fmt.Errorf("S5TIME %d ms should not be negative", ms)
+	}
+	for base, unit := range s5TimeBases {
+		count := (ms + unit/2) / unit
+		if count > 999 {
+			continue
+		}
+		raw, err := encodeBCD(count, 3)
+		return raw | uint64(base)<<12, err
+	}
+	return 0, fmt.Errorf("S5TIME %d ms exceeds 9990 s", ms)
+}
@@ -0,0 +1,145 @@
+package driver
+
+import (
+	"bytes"
+	"testing"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestEncodedValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		buffer   []byte
+		value    int64
+	}{
+		{name: "BCD byte", encoding: encodingBCD, buffer: []byte{0x42}, value: 42},
+		{name: "BCD word", encoding: encodingBCD, buffer: []byte{0x12, 0x34}, value: 1234},
+		{name: "BCD dword", encoding: encodingBCD, buffer: []byte{0x99, 0x99, 0x99, 0x99}, value: 99999999},
+		{name: "positive signed BCD", encoding: encodingSignedBCD, buffer: []byte{0x09, 0x99}, value: 999},
+		{name: "negative signed BCD", encoding: encodingSignedBCD, buffer: []byte{0xF1, 0x23}, value: -123},
+		{name: "negative signed BCD dword", encoding: encodingSignedBCD, buffer: []byte{0xF1, 0x23, 0x45, 0x67}, value: -1234567},
+		{name: "S5TIME 10 ms", encoding: encodingS5Time, buffer: []byte{0x01, 0x23}, value: 1230},
+		{name: "S5TIME 100 ms", encoding: encodingS5Time, buffer: []byte{0x11, 0x23}, value: 12300},
+		{name: "S5TIME 10 s", encoding: encodingS5Time, buffer: []byte{0x39, 0x99}, value: 9990000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := decodeValue(tt.encoding, tt.buffer)
+			if err != nil || value != tt.value {
+				t.Errorf("decodeValue() = %d, %v, want %d", value, err, tt.value)
+			}
+			encoded, err := encodeValue(tt.encoding, tt.value, len(tt.buffer))
+			if err != nil {
+				t.Fatalf("encodeValue() error = %v", err)
+			}
+			buffer := make([]byte, len(tt.buffer))
+			var raw uint64
+			switch v := encoded.(type) {
+			case uint8:
+				raw = uint64(v)
+			case uint16:
+				raw = uint64(v)
+			case uint32:
+				raw = uint64(v)
+			}
+			for i := range buffer {
+				buffer[len(buffer)-1-i] = byte(raw >> (8 * i))
+			}
+			if !bytes.Equal(buffer, tt.buffer) {
+				t.Errorf("encodeValue() = % X, want % X", buffer, tt.buffer)
+			}
+		})
+	}
+
+	if _, err := decodeValue(encodingBCD, []byte{0x1A}); err == nil {
+		t.Errorf("decodeValue() of an invalid digit should fail")
+	}
+	if _, err := decodeValue(encodingSignedBCD, []byte{0x81, 0x23}); err == nil {
+		t.Errorf("decodeValue() of an invalid sign should fail")
+	}
+	if _, err := encodeValue(encodingBCD, 10000, 2); err == nil {
+		t.Errorf("encodeValue() of 5 digits in a word should fail")
+	}
+	if _, err := encodeValue(encodingS5Time, 10000000, 2); err == nil {
+		t.Errorf("encodeValue() of S5TIME above 9990 s should fail")
+	}
+	if _, err := getEncoding(map[string]any{ENCODING: "bcd"}, common.ValueTypeFloat32); err == nil {
+		t.Errorf("getEncoding() of a Float32 resource should fail")
+	}
+	for _, valueType := range []string{common.ValueTypeUint8, common.ValueTypeInt16, common.ValueTypeUint16} {
+		if _, err := getEncoding(map[string]any{ENCODING: "S5TIME"}, valueType); err == nil {
+			t.Errorf("getEncoding() of a S5TIME %s resource should fail", valueType)
+		}
+	}
+	for _, valueType := range []string{common.ValueTypeUint32, common.ValueTypeInt64, common.ValueTypeFloat64} {
+		if _, err := getEncoding(map[string]any{ENCODING: "S5TIME"}, valueType); err != nil {
+			t.Errorf("getEncoding() of a S5TIME %s resource error = %v", valueType, err)
+		}
+	}
+}
+
+func TestEncodedReadWrite(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("counter", "DB1.DBW60", common.ValueTypeUint16),
+		newTestRequest("offset", "DB1.DBW62", common.ValueTypeInt16),
+		newTestRequest("duration", "DB1.DBW64", common.ValueTypeInt32),
+	}
+	reqs[0].Attributes[ENCODING] = "BCD"
+	reqs[1].Attributes[ENCODING] = "SignedBCD"
+	reqs[2].Attributes[ENCODING] = "S5TIME"
+
+	params := make([]*sdkModel.CommandValue, len(reqs))
+	for i, value := range []any{uint16(2024), int16(-42), int32(45000)} {
+		params[i], _ = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, reqs[i].Type, value)
+	}
+	if err := s.HandleWriteCommands(testDevice, protocols, reqs, params); err != nil {
+		t.Fatalf("HandleWriteCommands() error = %v", err)
+	}
+	data, _ := server.ReadArea(s7server.AreaDB, 1, 60, 6)
+	if !bytes.Equal(data, []byte{0x20, 0x24, 0xF0, 0x42, 0x14, 0x50}) {
+		t.Errorf("written values = % X", data)
+	}
+
+	values := readValues(t, s, protocols, reqs)
+	if values["counter"] != uint16(2024) || values["offset"] != int16(-42) || values["duration"] != int32(45000) {
+		t.Errorf("read values = %v", values)
+	}
+}
+
+func TestS5TimeReadWrite64Bit(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("delay", "DB1.DBW60", common.ValueTypeInt64),
+		newTestRequest("timeout", "DB1.DBW62", common.ValueTypeFloat64),
+	}
+	for _, req := range reqs {
+		req.Attributes[ENCODING] = "S5TIME"
+	}
+	if err := server.WriteArea(s7server.AreaDB, 1, 64, []byte{0xAB, 0xCD}); err != nil {
+		t.Fatal(err)
+	}
+
+	params := make([]*sdkModel.CommandValue, len(reqs))
+	for i, value := range []any{int64(9990000), float64(45000)} {
+		params[i], _ = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, reqs[i].Type, value)
+	}
+	if err := s.HandleWriteCommands(testDevice, protocols, reqs, params); err != nil {
+		t.Fatalf("HandleWriteCommands() error = %v", err)
+	}
+	// only the words are written, the next bytes are kept
+	data, _ := server.ReadArea(s7server.AreaDB, 1, 60, 6)
+	if !bytes.Equal(data, []byte{0x39, 0x99, 0x14, 0x50, 0xAB, 0xCD}) {
+		t.Errorf("written values = % X", data)
+	}
+
+	values := readValues(t, s, protocols, reqs)
+	if values["delay"] != int64(9990000) || values["timeout"] != float64(45000) {
+		t.Errorf("read values = %v", values)
+	}
+}
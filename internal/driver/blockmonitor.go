@@ -0,0 +1,329 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+// Block types of the S7 block info request
+const (
+	blockOB = 0x38
+	blockDB = 0x41
+	blockFC = 0x43
+	blockFB = 0x45
+)
+
+var blockTypeNames = map[int]string{
+	blockOB: "OB",
+	blockDB: "DB",
+	blockFC: "FC",
+	blockFB: "FB",
+}
+
+// BlockInfo is the recorded state of an OB/FB/FC/DB block
+type BlockInfo struct {
+	Type     string
+	Number   int
+	CheckSum int
+	CodeDate string
+	IntfDate string
+	MC7Size  int
+	LoadSize int
+}
+
+func (b BlockInfo) Name() string {
+	return fmt.Sprintf("%s%d", b.Type, b.Number)
+}
+
+func (b BlockInfo) toMap() map[string]any {
+	return map[string]any{
+		"block":    b.Name(),
+		"type":     b.Type,
+		"number":   b.Number,
+		"checksum": b.CheckSum,
+		"codeDate": b.CodeDate,
+		"intfDate": b.IntfDate,
+		"mc7Size":  b.MC7Size,
+		"loadSize": b.LoadSize,
+	}
+}
+
+// Block list request telegram, the block type is at byte 30
+var blockListTelegram = []byte{
+	3, 0, 0, 31, 2, 240, 128, 50, 7, 0, 0, 5, 0, 0, 8, 0, 6, 0, 1, 18, 4, 17, 67, 2, 0, 255, 9, 0, 2, 48,
+	0} // Block type
+
+// Block list next request telegram, the sequence number is at byte 24
+var blockListNextTelegram = []byte{
+	3, 0, 0, 33, 2, 240, 128, 50, 7, 0, 0, 6, 0, 0, 12, 0, 4, 0, 1, 18, 8, 18, 67, 2,
+	0, // Sequence
+	0, 0, 0, 0, 10, 0, 0, 0}
+
+// Block info request telegram, the block type is at byte 30 and the ASCII block number at byte 31
+var blockInfoTelegram = []byte{
+	3, 0, 0, 37, 2, 240, 128, 50, 7, 0, 0, 5, 0, 0, 8, 0, 12, 0, 1, 18, 4, 17, 67, 3, 0, 255, 9, 0, 8, 48,
+	0,                  // Block type
+	48, 48, 48, 48, 48, // ASCII block number
+	65}
+
+// Size of the block info data, up to the checksum
+const blockInfoSize = 70
+
+// Error code of a block list of a type without blocks
+const errNoBlockAvailable = 0xD20E
+
+// userDataError is the error answered by the PLC to a user data request, the connection is still usable
+type userDataError struct {
+	errorCode  uint16
+	returnCode byte
+}
+
+func (e *userDataError) Error() string {
+	return fmt.Sprintf("error code: 0x%04X, return code: 0x%02X", e.errorCode, e.returnCode)
+}
+
+// isTransportError returns true when the error is not an answer of the PLC, so the connection is dropped
+func isTransportError(err error) bool {
+	var plcErr *userDataError
+	return err != nil && !errors.As(err, &plcErr)
+}
+
+// sendUserData sends a user data request and returns the data of the response,
+// with the last data unit flag and the sequence number of the next fragment
+func (c *S7Client) sendUserData(request []byte) (data []byte, last bool, sequence byte, err error) {
+	response, err := c.Handler.Send(request)
+	if err != nil {
+		return nil, false, 0, err
+	}
+	if len(response) < 33 {
+		return nil, false, 0, fmt.Errorf("invalid response size %d", len(response))
+	}
+	if binary.BigEndian.Uint16(response[27:]) != 0 || response[29] != 0xFF {
+		return nil, false, 0, &userDataError{errorCode: binary.BigEndian.Uint16(response[27:]), returnCode: response[29]}
+	}
+	size := int(binary.BigEndian.Uint16(response[31:]))
+	if size > len(response)-33 {
+		return nil, false, 0, fmt.Errorf("data size %d exceeds the response size %d", size, len(response)-33)
+	}
+	// the last data unit flag is 0x00
+	return response[33 : 33+size], response[26] == 0x00, response[24], nil
+}
+
+// listBlocksOfType lists the numbers of the blocks of a type, the long lists come in several fragments.
+// The PLC answers the list of a type without blocks, like the FBs of a small program, with an error.
+func (c *S7Client) listBlocksOfType(blockType int) ([]int, error) {
+	request := make([]byte, len(blockListTelegram))
+	copy(request, blockListTelegram)
+	request[30] = byte(blockType)
+
+	var list []byte
+	for {
+		data, last, sequence, err := c.sendUserData(request)
+		var plcErr *userDataError
+		if errors.As(err, &plcErr) && plcErr.errorCode == errNoBlockAvailable {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list %s blocks failed, %w", blockTypeNames[blockType], err)
+		}
+		list = append(list, data...)
+		if last {
+			break
+		}
+		request = make([]byte, len(blockListNextTelegram))
+		copy(request, blockListNextTelegram)
+		request[24] = sequence
+	}
+
+	// block number, flags and language of each block
+	if len(list)%4 != 0 {
+		return nil, fmt.Errorf("invalid %s block list size %d", blockTypeNames[blockType], len(list))
+	}
+	numbers := make([]int, 0, len(list)/4)
+	for offset := 0; offset < len(list); offset += 4 {
+		numbers = append(numbers, int(binary.BigEndian.Uint16(list[offset:])))
+	}
+	return numbers, nil
+}
+
+// blockInfo reads the header of a block
+func (c *S7Client) blockInfo(blockType int, number int) (BlockInfo, error) {
+	request := make([]byte, len(blockInfoTelegram))
+	copy(request, blockInfoTelegram)
+	request[30] = byte(blockType)
+	copy(request[31:36], fmt.Sprintf("%05d", number))
+
+	data, _, _, err := c.sendUserData(request)
+	if err != nil {
+		return BlockInfo{}, err
+	}
+	if len(data) < blockInfoSize {
+		return BlockInfo{}, fmt.Errorf("invalid block info size %d", len(data))
+	}
+	return BlockInfo{
+		Type:     blockTypeNames[blockType],
+		Number:   number,
+		CheckSum: int(binary.BigEndian.Uint16(data[68:])),
+		CodeDate: blockDate(binary.BigEndian.Uint16(data[26:])),
+		IntfDate: blockDate(binary.BigEndian.Uint16(data[32:])),
+		MC7Size:  int(binary.BigEndian.Uint16(data[40:])),
+		LoadSize: int(binary.BigEndian.Uint32(data[14:])),
+	}, nil
+}
+
+// blockDate returns the date of a block timestamp, the days since 1984
+func blockDate(days uint16) string {
+	return time.Date(1984, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(days)).Format("02.01.2006")
+}
+
+// listBlocks lists the OB/FB/FC/DB blocks of the PLC with their checksum and timestamp. The types without blocks
+// are empty, and it fails when any other list fails, so a missing list is never taken for removed blocks.
+func (c *S7Client) listBlocks() (map[string]BlockInfo, error) {
+	if c == nil || c.Handler == nil {
+		return nil, fmt.Errorf("S7 client is not connected")
+	}
+
+	blocks := make(map[string]BlockInfo)
+	for _, blockType := range []int{blockOB, blockFB, blockFC, blockDB} {
+		numbers, err := c.listBlocksOfType(blockType)
+		if err != nil {
+			return nil, err
+		}
+		for _, number := range numbers {
+			block, err := c.blockInfo(blockType, number)
+			if err != nil {
+				return nil, fmt.Errorf("get block info of %s%d failed, error: %w", blockTypeNames[blockType], number, err)
+			}
+			blocks[block.Name()] = block
+		}
+	}
+	return blocks, nil
+}
+
+// diffBlocks compares two block lists, the result is nil when nothing is changed
+func diffBlocks(previous map[string]BlockInfo, current map[string]BlockInfo) map[string]any {
+	added := []any{}
+	removed := []any{}
+	modified := []any{}
+
+	for _, name := range sortedBlockNames(current) {
+		block := current[name]
+		old, ok := previous[name]
+		if !ok {
+			added = append(added, block.toMap())
+			continue
+		}
+		if old != block {
+			change := block.toMap()
+			change["previous"] = old.toMap()
+			modified = append(modified, change)
+		}
+	}
+	for _, name := range sortedBlockNames(previous) {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, previous[name].toMap())
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 && len(modified) == 0 {
+		return nil
+	}
+	return map[string]any{
+		"added":     added,
+		"removed":   removed,
+		"modified":  modified,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func sortedBlockNames(blocks map[string]BlockInfo) []string {
+	names := make([]string, 0, len(blocks))
+	for name := range blocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// monitorBlocks periodically lists the blocks of the device and emits an async event on every change
+func (s *Driver) monitorBlocks(ctx context.Context, deviceName string, protocols map[string]models.ProtocolProperties, interval time.Duration) {
+	s.lc.Infof("Block monitor of device %s started, interval: %v", deviceName, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous map[string]BlockInfo
+	for {
+		s7Client := s.getS7Client(deviceName, protocols)
+		current, err := s7Client.listBlocks()
+		if err != nil {
+			s.lc.Errorf("List blocks of device %s failed, error: %v", deviceName, err)
+			// the connection is kept when the PLC answered with an error
+			if isTransportError(err) {
+				s.dropS7Client(deviceName)
+			}
+		} else if previous == nil {
+			s.lc.Infof("Block monitor of device %s recorded %d blocks", deviceName, len(current))
+			previous = current
+		} else if changes := diffBlocks(previous, current); changes != nil {
+			s.lc.Warnf("Blocks of device %s are changed: %v", deviceName, changes)
+			previous = current
+			s.sendAsyncValue(ctx, deviceName, BLOCK_CHANGE_RESOURCE, common.ValueTypeObject, changes)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.lc.Infof("Block monitor of device %s stopped", deviceName)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendAsyncValue pushes a single reading of the resource to the SDK, the value is dropped when the context
+// is cancelled before the SDK takes it
+func (s *Driver) sendAsyncValue(ctx context.Context, deviceName string, resourceName string, valueType string, value any) {
+	result, err := sdkModel.NewCommandValue(resourceName, valueType, value)
+	if err != nil {
+		s.lc.Errorf("create async value of %s failed, error: %v", resourceName, err)
+		return
+	}
+	result.Origin = time.Now().UnixNano()
+	select {
+	case s.asyncCh <- &sdkModel.AsyncValues{
+		DeviceName:    deviceName,
+		SourceName:    resourceName,
+		CommandValues: []*sdkModel.CommandValue{result},
+	}:
+	case <-ctx.Done():
+		s.lc.Warnf("Async value of %s of device %s is dropped, the device tasks are stopped", resourceName, deviceName)
+	}
+}
+
+// getBlockMonitorInterval returns the block monitor interval, zero means disabled
+func getBlockMonitorInterval(pp models.ProtocolProperties) (time.Duration, error) {
+	value, ok := pp[BLOCK_MONITOR_INTERVAL]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	interval, err := cast.ToIntE(value)
+	if err != nil || interval < 0 {
+		return 0, fmt.Errorf("%s %v is not a positive integer", BLOCK_MONITOR_INTERVAL, value)
+	}
+	return time.Duration(interval) * time.Second, nil
+}
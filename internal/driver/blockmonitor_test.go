@@ -0,0 +1,147 @@
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestDiffBlocks(t *testing.T) {
+	ob1 := BlockInfo{Type: "OB", Number: 1, CheckSum: 0x1234, CodeDate: "01.02.2026"}
+	fc1 := BlockInfo{Type: "FC", Number: 1, CheckSum: 0x5678, CodeDate: "01.02.2026"}
+	db1 := BlockInfo{Type: "DB", Number: 1, CheckSum: 0x0001, CodeDate: "01.02.2026"}
+	fc1Modified := fc1
+	fc1Modified.CheckSum = 0x9ABC
+
+	previous := map[string]BlockInfo{ob1.Name(): ob1, fc1.Name(): fc1}
+
+	if changes := diffBlocks(previous, previous); changes != nil {
+		t.Errorf("diffBlocks() of the same blocks = %v, want nil", changes)
+	}
+
+	current := map[string]BlockInfo{fc1Modified.Name(): fc1Modified, db1.Name(): db1}
+	changes := diffBlocks(previous, current)
+	if changes == nil {
+		t.Fatalf("diffBlocks() = nil, want changes")
+	}
+	tests := []struct {
+		kind  string
+		block string
+	}{
+		{kind: "added", block: "DB1"},
+		{kind: "removed", block: "OB1"},
+		{kind: "modified", block: "FC1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			blocks, ok := changes[tt.kind].([]any)
+			if !ok || len(blocks) != 1 {
+				t.Fatalf("diffBlocks() %s = %v, want one block", tt.kind, changes[tt.kind])
+			}
+			if got := blocks[0].(map[string]any)["block"]; got != tt.block {
+				t.Errorf("diffBlocks() %s block = %v, want %v", tt.kind, got, tt.block)
+			}
+		})
+	}
+}
+
+func TestE2E_ListBlocks(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	codeDate := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	server.SetBlock(s7server.BlockOB, 1, s7server.Block{Data: make([]byte, 20), CheckSum: 0x1234, CodeDate: codeDate})
+	// enough DBs for a list in several fragments, the PLC has no FB and no FC
+	for number := 1; number <= 200; number++ {
+		server.SetBlock(s7server.BlockDB, number, s7server.Block{Data: make([]byte, 8)})
+	}
+
+	blocks, err := s.getS7Client(testDevice, protocols).listBlocks()
+	if err != nil {
+		t.Fatalf("listBlocks() error = %v", err)
+	}
+	if len(blocks) != 201 {
+		t.Errorf("listBlocks() = %d blocks, want 201", len(blocks))
+	}
+	want := BlockInfo{Type: "OB", Number: 1, CheckSum: 0x1234, CodeDate: "01.02.2026", IntfDate: "01.02.2026", MC7Size: 20, LoadSize: 56}
+	if blocks["OB1"] != want {
+		t.Errorf("listBlocks() OB1 = %+v, want %+v", blocks["OB1"], want)
+	}
+
+	server.SetBlockListError(s7server.BlockFC, 0xD241)
+	if _, err = s.getS7Client(testDevice, protocols).listBlocks(); err == nil || isTransportError(err) {
+		t.Errorf("listBlocks() with a failed FC list error = %v, want the error of the PLC", err)
+	}
+}
+
+func TestE2E_MonitorBlocksFailedList(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	server.SetBlock(s7server.BlockOB, 1, s7server.Block{Data: make([]byte, 20), CheckSum: 0x1234})
+	server.SetBlock(s7server.BlockFC, 1, s7server.Block{Data: make([]byte, 20), CheckSum: 0x5678})
+	server.SetBlock(s7server.BlockDB, 1, s7server.Block{Data: make([]byte, 8), CheckSum: 0x0001})
+	asyncCh := make(chan *sdkModel.AsyncValues, 16)
+	s.asyncCh = asyncCh
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.monitorBlocks(ctx, testDevice, protocols, 20*time.Millisecond)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// the DB list fails for a few cycles, the DB blocks are not removed
+	time.Sleep(100 * time.Millisecond)
+	s7Client := s.getS7Client(testDevice, protocols)
+	server.SetBlockListError(s7server.BlockDB, 0xD241)
+	time.Sleep(100 * time.Millisecond)
+	if len(asyncCh) != 0 {
+		t.Fatalf("%d block change events while the DB list fails, want none", len(asyncCh))
+	}
+	if s.getS7Client(testDevice, protocols) != s7Client {
+		t.Errorf("the connection should be kept when the PLC answers the list with an error")
+	}
+
+	server.ClearFaults()
+	server.SetBlock(s7server.BlockFC, 1, s7server.Block{Data: make([]byte, 20), CheckSum: 0x9ABC})
+	select {
+	case event := <-asyncCh:
+		changes := event.CommandValues[0].Value.(map[string]any)
+		if removed := changes["removed"].([]any); len(removed) != 0 {
+			t.Errorf("block change removed = %v, want none", removed)
+		}
+		if modified := changes["modified"].([]any); len(modified) != 1 || modified[0].(map[string]any)["block"] != "FC1" {
+			t.Errorf("block change modified = %v, want FC1", modified)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no block change event of the modified FC1")
+	}
+}
+
+func TestSendAsyncValueCancelled(t *testing.T) {
+	_, s, protocols := newTestServer(t)
+	s.asyncCh = make(chan *sdkModel.AsyncValues) // never received
+	s.startDeviceTasks(testDevice, protocols)
+	ctx := s.deviceContext(testDevice)
+
+	done := make(chan struct{})
+	go func() {
+		s.sendAsyncValue(ctx, testDevice, BLOCK_CHANGE_RESOURCE, common.ValueTypeObject, map[string]any{})
+		close(done)
+	}()
+	s.stopDeviceTasks(testDevice)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("sendAsyncValue() is blocked on the full async channel after the tasks are stopped")
+	}
+	if s.deviceContext(testDevice).Err() != nil {
+		t.Errorf("a device without tasks should get a context never cancelled")
+	}
+}
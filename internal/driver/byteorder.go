@@ -0,0 +1,104 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/robinson/gos7"
+
+	"github.com/spf13/cast"
+)
+
+// Byte orders of the 'ByteOrder' attribute, the letters are the bytes of a big-endian ABCD value
+const (
+	byteOrderABCD = "ABCD" // big-endian, the S7 order
+	byteOrderDCBA = "DCBA" // little-endian
+	byteOrderBADC = "BADC" // big-endian words with swapped bytes
+	byteOrderCDAB = "CDAB" // swapped words with big-endian bytes
+)
+
+// getByteOrder returns the byte order of the resource attributes, ABCD by default
+func getByteOrder(attributes map[string]any) (string, error) {
+	value, ok := attributes[BYTE_ORDER]
+	if !ok {
+		return byteOrderABCD, nil
+	}
+	order := strings.ToUpper(cast.ToString(value))
+	switch order {
+	case byteOrderABCD, byteOrderDCBA, byteOrderBADC, byteOrderCDAB:
+		return order, nil
+	default:
+		return "", fmt.Errorf("%s %v should be ABCD, DCBA, BADC or CDAB", BYTE_ORDER, value)
+	}
+}
+
+// is64Bit returns true for the value types of 8 bytes
+func is64Bit(valueType string) bool {
+	switch valueType {
+	case common.ValueTypeUint64, common.ValueTypeInt64, common.ValueTypeFloat64:
+		return true
+	}
+	return false
+}
+
+// resizeItem reads or writes 8 bytes from the start address of 64-bit values,
+// the S7 addresses of bytes, words and double words are shorter
+func resizeItem(item *gos7.S7DataItem, valueType string) {
+	if is64Bit(valueType) && item.WordLen != s7wlbit && item.WordLen != 0 {
+		item.WordLen = s7wlbyte
+		item.Amount = 8
+	}
+}
+
+// rawValueType returns the value type sizing the bytes at the address, the raw counts of the scaled
+// resources and the encoded values take the width of the address, even for the 64-bit resources
+func rawValueType(valueType string, scaled bool, encoding string) string {
+	if scaled || encoding != "" {
+		return ""
+	}
+	return valueType
+}
+
+// valueSize returns the number of bytes of the value at an address
+func valueSize(wordLength int, valueType string) int {
+	if wordLength == s7wlbit {
+		return 0
+	}
+	if is64Bit(valueType) {
+		return 8
+	}
+	switch wordLength {
+	case s7wlbyte, s7wlChar:
+		return 1
+	case s7wlword, s7wlint, s7wlcounter, s7wltimer:
+		return 2
+	default:
+		return 4
+	}
+}
+
+// reorderBytes converts a value between the byte order and the big-endian order in place,
+// each conversion is its own inverse so it serves both the reads and the writes
+func reorderBytes(value []byte, order string) {
+	switch order {
+	case byteOrderDCBA:
+		for i, j := 0, len(value)-1; i < j; i, j = i+1, j-1 {
+			value[i], value[j] = value[j], value[i]
+		}
+	case byteOrderBADC:
+		for i := 0; i+1 < len(value); i += 2 {
+			value[i], value[i+1] = value[i+1], value[i]
+		}
+	case byteOrderCDAB:
+		for i, j := 0, len(value)-2; i < j; i, j = i+2, j-2 {
+			value[i], value[i+1], value[j], value[j+1] = value[j], value[j+1], value[i], value[i+1]
+		}
+	}
+}
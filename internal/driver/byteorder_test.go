@@ -0,0 +1,87 @@
+package driver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestReorderBytes(t *testing.T) {
+	tests := []struct {
+		order string
+		value []byte
+		want  []byte
+	}{
+		{order: byteOrderABCD, value: []byte{1, 2, 3, 4}, want: []byte{1, 2, 3, 4}},
+		{order: byteOrderDCBA, value: []byte{1, 2, 3, 4}, want: []byte{4, 3, 2, 1}},
+		{order: byteOrderBADC, value: []byte{1, 2, 3, 4}, want: []byte{2, 1, 4, 3}},
+		{order: byteOrderCDAB, value: []byte{1, 2, 3, 4}, want: []byte{3, 4, 1, 2}},
+		{order: byteOrderDCBA, value: []byte{1, 2}, want: []byte{2, 1}},
+		{order: byteOrderCDAB, value: []byte{1, 2}, want: []byte{1, 2}},
+		{order: byteOrderBADC, value: []byte{1, 2, 3, 4, 5, 6, 7, 8}, want: []byte{2, 1, 4, 3, 6, 5, 8, 7}},
+		{order: byteOrderCDAB, value: []byte{1, 2, 3, 4, 5, 6, 7, 8}, want: []byte{7, 8, 5, 6, 3, 4, 1, 2}},
+	}
+	for _, tt := range tests {
+		reorderBytes(tt.value, tt.order)
+		if !bytes.Equal(tt.value, tt.want) {
+			t.Errorf("reorderBytes(%s) = %v, want %v", tt.order, tt.value, tt.want)
+		}
+	}
+
+	if _, err := getByteOrder(map[string]any{BYTE_ORDER: "cdab"}); err != nil {
+		t.Errorf("getByteOrder(cdab) error = %v", err)
+	}
+	if _, err := getByteOrder(map[string]any{BYTE_ORDER: "little"}); err == nil {
+		t.Errorf("getByteOrder(little) should fail")
+	}
+}
+
+func TestByteOrderReadWrite(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+
+	// a little-endian real and a word-swapped 64-bit integer written by a gateway
+	_ = server.WriteArea(s7server.AreaDB, 1, 40, binary.LittleEndian.AppendUint32(nil, math.Float32bits(21.5)))
+	_ = server.WriteArea(s7server.AreaDB, 1, 44, []byte{0x07, 0x08, 0x05, 0x06, 0x03, 0x04, 0x01, 0x02})
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("real", "DB1.DBD40", common.ValueTypeFloat32),
+		newTestRequest("counter", "DB1.DBD44", common.ValueTypeInt64),
+		newTestRequest("word", "DB1.DBW52", common.ValueTypeUint16),
+	}
+	reqs[0].Attributes[BYTE_ORDER] = byteOrderDCBA
+	reqs[1].Attributes[BYTE_ORDER] = byteOrderCDAB
+	reqs[2].Attributes[BYTE_ORDER] = byteOrderBADC
+
+	values := readValues(t, s, protocols, reqs)
+	if values["real"] != float32(21.5) || values["counter"] != int64(0x0102030405060708) {
+		t.Errorf("read values = %v", values)
+	}
+
+	params := make([]*sdkModel.CommandValue, len(reqs))
+	for i, value := range []any{float32(-3.25), int64(-2), uint16(0x1234)} {
+		params[i], _ = sdkModel.NewCommandValue(reqs[i].DeviceResourceName, reqs[i].Type, value)
+	}
+	if err := s.HandleWriteCommands(testDevice, protocols, reqs, params); err != nil {
+		t.Fatalf("HandleWriteCommands() error = %v", err)
+	}
+	data, _ := server.ReadArea(s7server.AreaDB, 1, 40, 14)
+	if got := math.Float32frombits(binary.LittleEndian.Uint32(data[0:4])); got != -3.25 {
+		t.Errorf("written real = %v, want -3.25", got)
+	}
+	if !bytes.Equal(data[4:12], []byte{0xFF, 0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Errorf("written counter = % X", data[4:12])
+	}
+	if !bytes.Equal(data[12:14], []byte{0x34, 0x12}) {
+		t.Errorf("written word = % X", data[12:14])
+	}
+
+	values = readValues(t, s, protocols, reqs)
+	if values["real"] != float32(-3.25) || values["counter"] != int64(-2) || values["word"] != uint16(0x1234) {
+		t.Errorf("read back values = %v", values)
+	}
+}
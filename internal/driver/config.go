@@ -0,0 +1,171 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+// ServiceConfig is the custom configuration of the service, the 'S7Config' section of configuration.yaml
+type ServiceConfig struct {
+	S7Config S7Config
+}
+
+// S7Config is the custom configuration of the driver
+type S7Config struct {
+	Writable S7Writable
+}
+
+// S7Writable are the tunables of the driver, they are reloaded when they change in the configuration provider
+type S7Writable struct {
+	// BatchSize is the maximum number of items of a multi-read or multi-write request, 1 to 20
+	BatchSize int
+	// RetryCount is the number of attempts of a request, the device is reconnected between them
+	RetryCount int
+	// Timeout and IdleTimeout of the connections of the devices without the 'Timeout' or 'IdleTimeout' protocol property
+	Timeout     string
+	IdleTimeout string
+	// ReconnectDelay is the wait before reconnecting a device after a failed request
+	ReconnectDelay string
+	// DownAfterFailures is the number of consecutive failed commands, after their retries, setting a device DOWN
+	DownAfterFailures int
+	// RecoveryInterval is the interval of the connects of a device DOWN, the first success sets it UP
+	RecoveryInterval string
+}
+
+// UpdateFromRaw updates the configuration from the raw configuration of the configuration provider
+func (c *ServiceConfig) UpdateFromRaw(rawConfig any) bool {
+	configuration, ok := rawConfig.(*ServiceConfig)
+	if !ok {
+		return false
+	}
+	*c = *configuration
+	return true
+}
+
+// driverConfig is the parsed S7Writable
+type driverConfig struct {
+	batchSize      int
+	retryCount     int
+	timeout        time.Duration
+	idleTimeout    time.Duration
+	reconnectDelay time.Duration
+
+	downAfterFailures int
+	recoveryInterval  time.Duration
+}
+
+// defaultConfig is the configuration until the custom configuration is loaded, and the values of its missing fields
+var defaultConfig = &driverConfig{
+	batchSize:         16,
+	retryCount:        3,
+	timeout:           30 * time.Second,
+	idleTimeout:       30 * time.Second,
+	downAfterFailures: 3,
+	recoveryInterval:  10 * time.Second,
+}
+
+// newDriverConfig validates and parses the writable configuration, the zero or empty fields,
+// like those of a configuration without the 'S7Config' section, take the default values
+func newDriverConfig(w S7Writable) (*driverConfig, error) {
+	config := *defaultConfig
+	for _, n := range []struct {
+		name  string
+		value int
+		min   int
+		max   int // 0 when unbounded
+		field *int
+	}{
+		{name: "BatchSize", value: w.BatchSize, min: 1, max: maxReadItems, field: &config.batchSize},
+		{name: "RetryCount", value: w.RetryCount, min: 1, field: &config.retryCount},
+		{name: "DownAfterFailures", value: w.DownAfterFailures, min: 1, field: &config.downAfterFailures},
+	} {
+		if n.value == 0 {
+			continue
+		}
+		if n.value < n.min && n.max == 0 {
+			return nil, fmt.Errorf("%s %d should be %d or more", n.name, n.value, n.min)
+		}
+		if n.max > 0 && (n.value < n.min || n.value > n.max) {
+			return nil, fmt.Errorf("%s %d should be %d to %d", n.name, n.value, n.min, n.max)
+		}
+		*n.field = n.value
+	}
+	for _, d := range []struct {
+		name     string
+		value    string
+		duration *time.Duration
+	}{
+		{name: "Timeout", value: w.Timeout, duration: &config.timeout},
+		{name: "IdleTimeout", value: w.IdleTimeout, duration: &config.idleTimeout},
+		{name: "ReconnectDelay", value: w.ReconnectDelay, duration: &config.reconnectDelay},
+		{name: "RecoveryInterval", value: w.RecoveryInterval, duration: &config.recoveryInterval},
+	} {
+		if d.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(d.value)
+		if err != nil || duration < 0 {
+			return nil, fmt.Errorf("%s %q should be a duration like 30s", d.name, d.value)
+		}
+		*d.duration = duration
+	}
+	if config.recoveryInterval == 0 {
+		return nil, fmt.Errorf("RecoveryInterval should be more than 0s")
+	}
+	return &config, nil
+}
+
+// getConfig returns the current configuration of the driver
+func (s *Driver) getConfig() *driverConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.config == nil {
+		return defaultConfig
+	}
+	return s.config
+}
+
+// updateWritableConfig applies the changes of the 'S7Config/Writable' section, the read plans are compiled again
+// with the new batch size and the timeouts apply to the next connections
+func (s *Driver) updateWritableConfig(rawWritableConfig any) {
+	updated, ok := rawWritableConfig.(*S7Writable)
+	if !ok {
+		s.lc.Errorf("unable to process custom config updates: can not cast raw config to type 'S7Writable'")
+		return
+	}
+	s.lc.Infof("Received configuration updates for '%s' section", CUSTOM_CONFIG_WRITABLE)
+
+	config, err := newDriverConfig(*updated)
+	if err != nil {
+		s.lc.Errorf("Invalid '%s' configuration, the previous one is kept, error: %v", CUSTOM_CONFIG_WRITABLE, err)
+		return
+	}
+	s.mu.Lock()
+	previous := s.config
+	s.config = config
+	if previous == nil || !reflect.DeepEqual(*previous, *config) {
+		s.readPlans = make(map[string]map[string]*readPlan)
+	}
+	s.mu.Unlock()
+	s.lc.Infof("Driver configuration updated: %+v", *updated)
+}
+
+// reconnect drops the connection of a device after a failed request, waits the reconnect delay and connects it again
+func (s *Driver) reconnect(deviceName string, protocols map[string]models.ProtocolProperties) *S7Client {
+	s.dropS7Client(deviceName)
+	s.recordConnection(deviceName, false)
+	s.getDeviceMetrics(deviceName).reconnects.Inc(1)
+	if delay := s.getConfig().reconnectDelay; delay > 0 {
+		time.Sleep(delay)
+	}
+	return s.getS7Client(deviceName, protocols)
+}
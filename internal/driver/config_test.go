@@ -0,0 +1,79 @@
+package driver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/clients/logger"
+)
+
+func TestNewDriverConfig(t *testing.T) {
+	writable := S7Writable{BatchSize: 8, RetryCount: 2, Timeout: "5s", IdleTimeout: "1m", ReconnectDelay: "500ms", DownAfterFailures: 5, RecoveryInterval: "20s"}
+	config, err := newDriverConfig(writable)
+	if err != nil {
+		t.Fatalf("newDriverConfig() error = %v", err)
+	}
+	want := driverConfig{batchSize: 8, retryCount: 2, timeout: 5 * time.Second, idleTimeout: time.Minute, reconnectDelay: 500 * time.Millisecond,
+		downAfterFailures: 5, recoveryInterval: 20 * time.Second}
+	if *config != want {
+		t.Errorf("newDriverConfig() = %+v, want %+v", *config, want)
+	}
+
+	for _, tt := range []struct {
+		name string
+		edit func(w *S7Writable)
+		want string
+	}{
+		{name: "negative batch size", edit: func(w *S7Writable) { w.BatchSize = -1 }, want: "BatchSize"},
+		{name: "batch size 21", edit: func(w *S7Writable) { w.BatchSize = 21 }, want: "BatchSize"},
+		{name: "negative retry", edit: func(w *S7Writable) { w.RetryCount = -1 }, want: "RetryCount"},
+		{name: "timeout", edit: func(w *S7Writable) { w.Timeout = "30" }, want: "Timeout"},
+		{name: "negative delay", edit: func(w *S7Writable) { w.ReconnectDelay = "-1s" }, want: "ReconnectDelay"},
+		{name: "negative down", edit: func(w *S7Writable) { w.DownAfterFailures = -1 }, want: "DownAfterFailures"},
+		{name: "no recovery", edit: func(w *S7Writable) { w.RecoveryInterval = "0s" }, want: "RecoveryInterval"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := writable
+			tt.edit(&w)
+			if _, err := newDriverConfig(w); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("newDriverConfig() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	// a configuration without the 'S7Config' section takes the defaults
+	if config, err = newDriverConfig(S7Writable{}); err != nil || *config != *defaultConfig {
+		t.Errorf("newDriverConfig() of an empty configuration = %+v, %v, want the defaults", config, err)
+	}
+	if config, err = newDriverConfig(S7Writable{BatchSize: 4, ReconnectDelay: "1s"}); err != nil ||
+		config.batchSize != 4 || config.reconnectDelay != time.Second || config.retryCount != defaultConfig.retryCount {
+		t.Errorf("newDriverConfig() of a partial configuration = %+v, %v", config, err)
+	}
+}
+
+func TestUpdateWritableConfig(t *testing.T) {
+	s := &Driver{lc: logger.NewClient("S7", "Error"), readPlans: map[string]map[string]*readPlan{testDevice: {}}}
+	if s.getConfig() != defaultConfig {
+		t.Errorf("getConfig() should return the defaults until the configuration is loaded")
+	}
+
+	s.updateWritableConfig(&S7Writable{BatchSize: 4, RetryCount: 1, Timeout: "1s", IdleTimeout: "1s", ReconnectDelay: "0s", DownAfterFailures: 3, RecoveryInterval: "10s"})
+	if config := s.getConfig(); config.batchSize != 4 || config.retryCount != 1 {
+		t.Errorf("getConfig() = %+v", *config)
+	}
+	if len(s.readPlans) != 0 {
+		t.Errorf("the read plans should be compiled again with the new batch size")
+	}
+
+	s.updateWritableConfig(&S7Writable{BatchSize: 40, RetryCount: 1, Timeout: "1s", IdleTimeout: "1s", ReconnectDelay: "0s", DownAfterFailures: 3, RecoveryInterval: "10s"})
+	if config := s.getConfig(); config.batchSize != 4 {
+		t.Errorf("an invalid update should keep the previous configuration, got %+v", *config)
+	}
+
+	var raw any = &ServiceConfig{S7Config: S7Config{Writable: S7Writable{BatchSize: 12}}}
+	service := &ServiceConfig{}
+	if !service.UpdateFromRaw(raw) || service.S7Config.Writable.BatchSize != 12 || service.UpdateFromRaw(S7Writable{}) {
+		t.Errorf("UpdateFromRaw() should accept a *ServiceConfig only")
+	}
+}
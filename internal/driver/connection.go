@@ -0,0 +1,144 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/robinson/gos7"
+	"github.com/spf13/cast"
+)
+
+// Connection types, the high byte of the remote TSAP
+const (
+	connectionTypePG    = 0x01
+	connectionTypeOP    = 0x02
+	connectionTypeBasic = 0x03
+)
+
+// The default local TSAP of the PG and OP connections
+const defaultLocalTSAP = 0x0100
+
+var connectionTypes = map[string]int{
+	"PG":       connectionTypePG,
+	"OP":       connectionTypeOP,
+	"S7-BASIC": connectionTypeBasic,
+	"BASIC":    connectionTypeBasic,
+}
+
+// ConnectionInfo is the ISO-on-TCP addressing of a device
+type ConnectionInfo struct {
+	Rack           int
+	Slot           int
+	ConnectionType int
+	LocalTSAP      uint16
+	RemoteTSAP     uint16
+}
+
+// getConnectionInfo returns the connection type and TSAPs from the protocol properties.
+// An explicit 'RemoteTSAP' replaces rack, slot and connection type.
+func getConnectionInfo(pp models.ProtocolProperties) (*ConnectionInfo, error) {
+	info := &ConnectionInfo{
+		ConnectionType: connectionTypePG,
+		LocalTSAP:      defaultLocalTSAP,
+	}
+
+	if value, ok := pp[CONNECTION_TYPE]; ok && value != "" {
+		connectionType, ok := connectionTypes[strings.ToUpper(cast.ToString(value))]
+		if !ok {
+			return nil, fmt.Errorf("%s %v should be PG, OP or S7-Basic", CONNECTION_TYPE, value)
+		}
+		info.ConnectionType = connectionType
+	}
+
+	if value, ok := pp[LOCAL_TSAP]; ok && value != "" {
+		tsap, err := parseTSAP(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s %v is invalid, error: %v", LOCAL_TSAP, value, err)
+		}
+		info.LocalTSAP = tsap
+	}
+
+	if value, ok := pp[REMOTE_TSAP]; ok && value != "" {
+		tsap, err := parseTSAP(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s %v is invalid, error: %v", REMOTE_TSAP, value, err)
+		}
+		// remote TSAP = connection type << 8 + rack * 0x20 + slot
+		info.RemoteTSAP = tsap
+		info.ConnectionType = int(tsap >> 8)
+		info.Rack = int(tsap&0xFF) >> 5
+		info.Slot = int(tsap & 0x1F)
+		return info, nil
+	}
+
+	if _, ok := pp[RACK]; !ok {
+		return nil, fmt.Errorf("%s or %s not found in Protocol", RACK, REMOTE_TSAP)
+	}
+	if _, ok := pp[SLOT]; !ok {
+		return nil, fmt.Errorf("%s or %s not found in Protocol", SLOT, REMOTE_TSAP)
+	}
+	rack, err := cast.ToIntE(pp[RACK])
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an integer in Protocol, error: %v", RACK, err)
+	}
+	slot, err := cast.ToIntE(pp[SLOT])
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an integer in Protocol, error: %v", SLOT, err)
+	}
+	if rack < 0 || rack > 7 || slot < 0 || slot > 31 {
+		return nil, fmt.Errorf("rack %d should be 0 to 7 and slot %d should be 0 to 31", rack, slot)
+	}
+	info.Rack = rack
+	info.Slot = slot
+	info.RemoteTSAP = uint16(info.ConnectionType)<<8 + uint16(rack*0x20+slot)
+	return info, nil
+}
+
+// parseTSAP parses a TSAP like 0x0300, 0300 or 03.00 (LOGO!Soft notation), numbers are used as they are
+func parseTSAP(value any) (uint16, error) {
+	text, ok := value.(string)
+	if !ok {
+		tsap, err := cast.ToUint16E(value)
+		if err != nil {
+			return 0, err
+		}
+		return tsap, nil
+	}
+
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = strings.TrimPrefix(text, "0x")
+	if high, low, found := strings.Cut(text, "."); found {
+		highByte, err := strconv.ParseUint(high, 16, 8)
+		if err != nil {
+			return 0, err
+		}
+		lowByte, err := strconv.ParseUint(low, 16, 8)
+		if err != nil {
+			return 0, err
+		}
+		return uint16(highByte<<8 | lowByte), nil
+	}
+	tsap, err := strconv.ParseUint(text, 16, 16)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(tsap), nil
+}
+
+// newTCPClientHandler creates the handler with the connection type and TSAPs of the device
+func newTCPClientHandler(address string, info *ConnectionInfo) (*gos7.TCPClientHandler, error) {
+	handler := gos7.NewTCPClientHandlerWithTSAP(address, info.LocalTSAP, info.RemoteTSAP)
+	if handler == nil {
+		return nil, fmt.Errorf("can't create TCP client handler for %s", address)
+	}
+	return handler, nil
+}
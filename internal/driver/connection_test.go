@@ -0,0 +1,90 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestGetConnectionInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		pp      models.ProtocolProperties
+		want    *ConnectionInfo
+		wantErr bool
+	}{
+		{
+			name: "default PG connection",
+			pp:   models.ProtocolProperties{RACK: 0, SLOT: 1},
+			want: &ConnectionInfo{Rack: 0, Slot: 1, ConnectionType: connectionTypePG, LocalTSAP: 0x0100, RemoteTSAP: 0x0101},
+		},
+		{
+			name: "OP connection",
+			pp:   models.ProtocolProperties{RACK: "0", SLOT: "2", CONNECTION_TYPE: "op"},
+			want: &ConnectionInfo{Rack: 0, Slot: 2, ConnectionType: connectionTypeOP, LocalTSAP: 0x0100, RemoteTSAP: 0x0202},
+		},
+		{
+			name: "LOGO! TSAPs",
+			pp:   models.ProtocolProperties{LOCAL_TSAP: "03.00", REMOTE_TSAP: "0x0200"},
+			want: &ConnectionInfo{Rack: 0, Slot: 0, ConnectionType: 0x02, LocalTSAP: 0x0300, RemoteTSAP: 0x0200},
+		},
+		{
+			name: "numeric TSAPs",
+			pp:   models.ProtocolProperties{LOCAL_TSAP: float64(0x1000), REMOTE_TSAP: float64(0x1001)},
+			want: &ConnectionInfo{Rack: 0, Slot: 1, ConnectionType: 0x10, LocalTSAP: 0x1000, RemoteTSAP: 0x1001},
+		},
+		{
+			name:    "unknown connection type",
+			pp:      models.ProtocolProperties{RACK: 0, SLOT: 1, CONNECTION_TYPE: "HMI"},
+			wantErr: true,
+		},
+		{
+			name:    "missing slot",
+			pp:      models.ProtocolProperties{RACK: 0},
+			wantErr: true,
+		},
+		{
+			name:    "invalid TSAP",
+			pp:      models.ProtocolProperties{REMOTE_TSAP: "02.XY"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getConnectionInfo(tt.pp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getConnectionInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getConnectionInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTCPClientHandler_LocalTSAP(t *testing.T) {
+	server := s7server.NewServer()
+	if err := server.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer server.Stop()
+
+	info := &ConnectionInfo{ConnectionType: 0x02, LocalTSAP: 0x0300, RemoteTSAP: 0x0200}
+	handler, err := newTCPClientHandler(server.Addr(), info)
+	if err != nil {
+		t.Fatalf("newTCPClientHandler() error = %v", err)
+	}
+	if err = handler.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer handler.Close()
+	if handler.ConnectionType != 0x02 {
+		t.Errorf("ConnectionType = %d, want 2", handler.ConnectionType)
+	}
+	if local, remote := server.TSAPs(); local != 0x0300 || remote != 0x0200 {
+		t.Errorf("TSAPs = 0x%04X, 0x%04X, want 0x0300, 0x0200", local, remote)
+	}
+}
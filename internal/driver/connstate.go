@@ -0,0 +1,236 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+// connectionState is the health of the connection of a device
+type connectionState struct {
+	failures         int // consecutive failed commands, batches and probes
+	down             bool
+	lastError        string
+	lastErrorTime    time.Time
+	lastSuccessTime  time.Time
+	lastActivityTime time.Time          // last request or connect, for the keepalive
+	cancelProbe      context.CancelFunc // stops the recovery probe of a device down
+}
+
+// value returns the state as the value of the __ConnectionState resource
+func (c *connectionState) value() map[string]any {
+	timestamp := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339Nano)
+	}
+	state := models.Up
+	if c.down {
+		state = models.Down
+	}
+	return map[string]any{
+		"operatingState":      state,
+		"consecutiveFailures": c.failures,
+		"lastError":           c.lastError,
+		"lastErrorTime":       timestamp(c.lastErrorTime),
+		"lastSuccessTime":     timestamp(c.lastSuccessTime),
+	}
+}
+
+// getConnectionState returns the connection state of a device, created on the first use
+func (s *Driver) getConnectionState(deviceName string) *connectionState {
+	if s.connStates == nil {
+		s.connStates = make(map[string]*connectionState)
+	}
+	c := s.connStates[deviceName]
+	if c == nil {
+		c = &connectionState{}
+		s.connStates[deviceName] = c
+	}
+	return c
+}
+
+// updateConnectionState records the result of a command, a batch or a probe of a device, after its retries.
+// The device is set DOWN after 'DownAfterFailures' consecutive failures, and UP again by the first success.
+func (s *Driver) updateConnectionState(deviceName string, err error) {
+	threshold := s.getConfig().downAfterFailures
+	now := time.Now()
+
+	s.mu.Lock()
+	c := s.getConnectionState(deviceName)
+	c.lastActivityTime = now
+	changed := false
+	if err != nil {
+		c.failures++
+		c.lastError = err.Error()
+		c.lastErrorTime = now
+		changed = !c.down && c.failures >= threshold
+		c.down = c.down || changed
+	} else {
+		c.failures = 0
+		c.lastSuccessTime = now
+		changed = c.down
+		c.down = false
+	}
+	s.mu.Unlock()
+
+	if changed {
+		s.setOperatingState(deviceName)
+	}
+}
+
+// loadConnectionState takes over the DOWN state of a device from core-metadata, like after a restart,
+// so the device is probed and set UP again once it answers
+func (s *Driver) loadConnectionState(deviceName string, operatingState models.OperatingState) {
+	if operatingState != models.Down {
+		return
+	}
+	s.mu.Lock()
+	c := s.getConnectionState(deviceName)
+	changed := !c.down
+	c.down = true
+	s.mu.Unlock()
+	if changed {
+		s.lc.Infof("Device %s is DOWN in core-metadata, probing its connection", deviceName)
+		s.startProbe(deviceName)
+	}
+}
+
+// syncConnectionState takes over the OperatingState of an added or updated device from core-metadata
+func (s *Driver) syncConnectionState(deviceName string) {
+	if s.sdk == nil {
+		return
+	}
+	if device, err := s.sdk.GetDeviceByName(deviceName); err == nil {
+		s.loadConnectionState(deviceName, device.OperatingState)
+	}
+}
+
+// setOperatingState sets the OperatingState of a device to its connection state in core-metadata,
+// sends the state as a __ConnectionState async value, and probes the connection of a device down
+func (s *Driver) setOperatingState(deviceName string) {
+	s.mu.Lock()
+	c := s.getConnectionState(deviceName)
+	down, value := c.down, c.value()
+	s.mu.Unlock()
+
+	var state models.OperatingState = models.Up
+	if down {
+		state = models.Down
+		s.lc.Warnf("Device %s is DOWN after %v consecutive failures, last error: %v", deviceName, value["consecutiveFailures"], value["lastError"])
+		s.startProbe(deviceName)
+	} else {
+		s.lc.Infof("Device %s is UP, its connection is recovered", deviceName)
+		s.stopProbe(deviceName)
+	}
+
+	// the driver runs without the SDK in the tests
+	if s.sdk == nil {
+		return
+	}
+	if err := s.sdk.UpdateDeviceOperatingState(deviceName, state); err != nil {
+		s.lc.Errorf("Update OperatingState of device %s to %s failed, error: %v", deviceName, state, err)
+	}
+	s.sendAsyncValue(s.deviceContext(deviceName), deviceName, CONNECTION_STATE_RESOURCE, common.ValueTypeObject, value)
+}
+
+// startProbe starts the recovery probe of a device down, the SDK doesn't send the commands of a device down
+func (s *Driver) startProbe(deviceName string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	c := s.getConnectionState(deviceName)
+	// locked devices are not connected
+	if c.cancelProbe != nil || s.locked[deviceName] {
+		s.mu.Unlock()
+		cancel()
+		return
+	}
+	c.cancelProbe = cancel
+	s.mu.Unlock()
+	go s.probeConnection(ctx, deviceName)
+}
+
+// stopProbe stops the recovery probe of a device
+func (s *Driver) stopProbe(deviceName string) {
+	s.mu.Lock()
+	var cancel context.CancelFunc
+	if c := s.connStates[deviceName]; c != nil {
+		cancel, c.cancelProbe = c.cancelProbe, nil
+	}
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// probeConnection connects a device down and reads a byte every 'RecoveryInterval', a successful read sets it UP
+func (s *Driver) probeConnection(ctx context.Context, deviceName string) {
+	ticker := time.NewTicker(s.getConfig().recoveryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if s.sdk == nil || s.isLocked(deviceName) {
+			return
+		}
+		device, err := s.sdk.GetDeviceByName(deviceName)
+		if err != nil {
+			s.lc.Warnf("Connection probe of device %s stopped, error: %v", deviceName, err)
+			return
+		}
+		s.lc.Debugf("Probing the connection of device %s", deviceName)
+		s.dropS7Client(deviceName)
+		// an item error like a protected area still proves the connection
+		s.updateConnectionState(deviceName, readKeepAlive(s.getS7Client(deviceName, device.Protocols)))
+	}
+}
+
+// removeConnectionState drops the connection state of a removed device
+func (s *Driver) removeConnectionState(deviceName string) {
+	s.stopProbe(deviceName)
+	s.mu.Lock()
+	delete(s.connStates, deviceName)
+	s.mu.Unlock()
+}
+
+// readConnectionState returns the __ConnectionState resources of the requests
+func (s *Driver) readConnectionState(deviceName string, reqs []sdkModel.CommandRequest) (res []*sdkModel.CommandValue) {
+	s.mu.Lock()
+	value := s.getConnectionState(deviceName).value()
+	s.mu.Unlock()
+	for _, req := range reqs {
+		result, err := sdkModel.NewCommandValue(req.DeviceResourceName, common.ValueTypeObject, value)
+		if err != nil {
+			s.lc.Errorf("create value of %s failed, error: %v", req.DeviceResourceName, err)
+			continue
+		}
+		result.Origin = time.Now().UnixNano()
+		res = append(res, result)
+	}
+	return res
+}
+
+// splitResource splits the requests of a resource from the other requests
+func splitResource(reqs []sdkModel.CommandRequest, resourceName string) (otherReqs []sdkModel.CommandRequest, resourceReqs []sdkModel.CommandRequest) {
+	for _, req := range reqs {
+		if req.DeviceResourceName == resourceName {
+			resourceReqs = append(resourceReqs, req)
+		} else {
+			otherReqs = append(otherReqs, req)
+		}
+	}
+	return otherReqs, resourceReqs
+}
@@ -0,0 +1,73 @@
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+func TestUpdateConnectionState(t *testing.T) {
+	_, s, _ := newTestServer(t)
+	for i := 1; i <= 3; i++ {
+		s.updateConnectionState(testDevice, errors.New("i/o timeout"))
+		if down := s.connStates[testDevice].down; down != (i == 3) {
+			t.Errorf("down after %d failures = %v", i, down)
+		}
+	}
+	if s.connStates[testDevice].cancelProbe == nil {
+		t.Errorf("a device DOWN should be probed")
+	}
+	value := s.connStates[testDevice].value()
+	if value["operatingState"] != models.Down || value["lastError"] != "i/o timeout" || value["lastSuccessTime"] != "" {
+		t.Errorf("connection state = %v", value)
+	}
+
+	s.updateConnectionState(testDevice, nil)
+	c := s.connStates[testDevice]
+	if c.down || c.failures != 0 || c.cancelProbe != nil || c.lastSuccessTime.IsZero() {
+		t.Errorf("connection state after a success = %+v", *c)
+	}
+
+	s.loadConnectionState(testDevice, models.Down)
+	if !s.connStates[testDevice].down {
+		t.Errorf("the DOWN state of core-metadata should be taken over")
+	}
+	s.removeConnectionState(testDevice)
+	if _, ok := s.connStates[testDevice]; ok {
+		t.Errorf("removeConnectionState() should drop the state of the device")
+	}
+}
+
+func TestE2E_ConnectionState(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("word", "DB1.DBW0", common.ValueTypeUint16),
+		{DeviceResourceName: CONNECTION_STATE_RESOURCE, Type: common.ValueTypeObject},
+	}
+	state := readValues(t, s, protocols, reqs)[CONNECTION_STATE_RESOURCE].(map[string]any)
+	if state["operatingState"] != models.Up || state["consecutiveFailures"] != 0 || state["lastSuccessTime"] == "" {
+		t.Errorf("connection state = %v", state)
+	}
+
+	// a failed read counts once, after its retries and reconnects, and the third one sets the device DOWN
+	for i := 1; i <= 3; i++ {
+		server.DropNextRequests(10)
+		state = readValues(t, s, protocols, reqs)[CONNECTION_STATE_RESOURCE].(map[string]any)
+		want := models.Up
+		if i == 3 {
+			want = models.Down
+		}
+		if state["operatingState"] != want || state["consecutiveFailures"] != i || state["lastError"] == "" {
+			t.Errorf("connection state after %d failed reads = %v", i, state)
+		}
+	}
+
+	server.ClearFaults()
+	readValues(t, s, protocols, reqs[:1])
+	if s.connStates[testDevice].down {
+		t.Errorf("the device should be UP after a successful read")
+	}
+}
@@ -21,4 +21,81 @@ const (
 	STARTING_ADDRESS = "StartingAddress"
 	LENGTH           = "Length"
 	POS              = "Pos"
+
+	FAMILY                 = "Family"
+	CONNECTION_TYPE        = "ConnectionType"
+	LOCAL_TSAP             = "LocalTSAP"
+	REMOTE_TSAP            = "RemoteTSAP"
+	PASSWORD               = "Password"
+	BLOCK_MONITOR_INTERVAL = "BlockMonitorInterval"
+	POLL_INTERVAL          = "PollInterval"
+	INTEGRITY_INTERVAL     = "IntegrityInterval"
+	KEEPALIVE_INTERVAL     = "KeepAliveInterval"
+
+	HEARTBEAT_NODE_NAME      = "HeartbeatNodeName"
+	HEARTBEAT_INTERVAL       = "HeartbeatInterval"
+	HOST_HEARTBEAT_NODE_NAME = "HostHeartbeatNodeName"
+	HOST_HEARTBEAT_INTERVAL  = "HostHeartbeatInterval"
+
+	SYMBOL_FILE = "SymbolFile"
+)
+
+// Key of the session password in the secret named by the 'Password' protocol property
+const (
+	SECRET_PASSWORD_KEY = "password"
+)
+
+// Constants related to device resource attributes
+const (
+	SZL_ID       = "SZLID"
+	SZL_INDEX    = "SZLIndex"
+	BLOCK_BACKUP = "BlockBackup"
+
+	COV              = "COV"
+	DEADBAND         = "Deadband"
+	DEADBAND_PERCENT = "DeadbandPercent"
+	EDGE             = "Edge"
+
+	ALARMS      = "Alarms"
+	ALARM_FILE  = "AlarmFile"
+	ALARM_WORDS = "AlarmWords"
+
+	RAW_MIN = "RawMin"
+	RAW_MAX = "RawMax"
+	EU_MIN  = "EUMin"
+	EU_MAX  = "EUMax"
+
+	BYTE_ORDER = "ByteOrder"
+	ENCODING   = "Encoding"
+)
+
+// Constants related to driver configuration
+const (
+	BACKUP_DIR = "BackupDir"
+
+	CUSTOM_CONFIG          = "S7Config"
+	CUSTOM_CONFIG_WRITABLE = "S7Config/Writable"
+)
+
+// Metrics of the devices, enabled in the 'Writable/Telemetry/Metrics' section of the configuration
+const (
+	METRIC_READ_REQUESTS    = "S7ReadRequests"
+	METRIC_WRITE_REQUESTS   = "S7WriteRequests"
+	METRIC_REQUEST_ERRORS   = "S7RequestErrors"
+	METRIC_ITEM_ERRORS      = "S7ItemErrors"
+	METRIC_ROUND_TRIP       = "S7RoundTrip"
+	METRIC_BATCH_SIZE       = "S7BatchSize"
+	METRIC_RECONNECTS       = "S7Reconnects"
+	METRIC_CONNECTION_STATE = "S7ConnectionState"
+	METRIC_BYTES_READ       = "S7BytesRead"
+	METRIC_BYTES_WRITTEN    = "S7BytesWritten"
+)
+
+// Resources which receive the async events of the driver
+const (
+	BLOCK_CHANGE_RESOURCE = "__BlockChange"
+	HEARTBEAT_RESOURCE    = "__Heartbeat"
+	ALARM_RESOURCE        = "__Alarm"
+
+	CONNECTION_STATE_RESOURCE = "__ConnectionState"
 )
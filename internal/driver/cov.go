@@ -0,0 +1,222 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+// covResource is a resource polled by the change-of-value poller with its last published value,
+// or the last state of the bit for the resources having an 'Edge'
+type covResource struct {
+	request         sdkModel.CommandRequest
+	deadband        float64
+	deadbandPercent float64
+	last            *sdkModel.CommandValue
+
+	edge       string
+	state      *bool
+	risingTime time.Time
+}
+
+// newCOVResource creates the poller state of the resource from its attributes
+func newCOVResource(resource models.DeviceResource) (*covResource, error) {
+	r := &covResource{
+		request: sdkModel.CommandRequest{
+			DeviceResourceName: resource.Name,
+			Attributes:         resource.Attributes,
+			Type:               resource.Properties.ValueType,
+		},
+	}
+	var err error
+	if r.edge, err = getEdge(resource.Attributes, resource.Properties.ValueType); err != nil {
+		return nil, fmt.Errorf("invalid edge of resource %s, error: %v", resource.Name, err)
+	}
+	if value, ok := resource.Attributes[DEADBAND]; ok {
+		if r.deadband, err = cast.ToFloat64E(value); err != nil || r.deadband < 0 {
+			return nil, fmt.Errorf("%s %v of resource %s is not a positive number", DEADBAND, value, resource.Name)
+		}
+	}
+	if value, ok := resource.Attributes[DEADBAND_PERCENT]; ok {
+		if r.deadbandPercent, err = cast.ToFloat64E(value); err != nil || r.deadbandPercent < 0 {
+			return nil, fmt.Errorf("%s %v of resource %s is not a positive number", DEADBAND_PERCENT, value, resource.Name)
+		}
+	}
+	return r, nil
+}
+
+// changed returns true when the value differs from the last published one beyond the deadbands
+func (r *covResource) changed(value *sdkModel.CommandValue) bool {
+	if r.last == nil {
+		return true
+	}
+	current, err1 := cast.ToFloat64E(value.Value)
+	previous, err2 := cast.ToFloat64E(r.last.Value)
+	if err1 != nil || err2 != nil || value.Type == common.ValueTypeBool {
+		return !reflect.DeepEqual(value.Value, r.last.Value)
+	}
+	delta := math.Abs(current - previous)
+	if delta == 0 {
+		return false
+	}
+	if r.deadband > 0 && delta <= r.deadband {
+		return false
+	}
+	if r.deadbandPercent > 0 && delta <= math.Abs(previous)*r.deadbandPercent/100 {
+		return false
+	}
+	return true
+}
+
+// getCOVResources returns the resources of the device profile having the 'COV', 'Edge' or 'AlarmWords' attribute
+func (s *Driver) getCOVResources(deviceName string) ([]*covResource, error) {
+	if s.sdk == nil {
+		return nil, fmt.Errorf("device service SDK is not available")
+	}
+	device, err := s.sdk.GetDeviceByName(deviceName)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.sdk.GetProfileByName(device.ProfileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*covResource
+	for _, resource := range profile.DeviceResources {
+		_, edge := resource.Attributes[EDGE]
+		_, alarms := resource.Attributes[ALARM_WORDS]
+		if !(cast.ToBool(resource.Attributes[COV]) || edge || alarms) || !strings.Contains(resource.Properties.ReadWrite, common.ReadWrite_R) {
+			continue
+		}
+		r, err := newCOVResource(resource)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, r)
+	}
+	return resources, nil
+}
+
+// reloadCOVResources reloads the resources from the device profile, so the profile updates are picked up,
+// and keeps the last published value and bit state of the resources still polled
+func (s *Driver) reloadCOVResources(deviceName string, previous []*covResource) []*covResource {
+	resources, err := s.getCOVResources(deviceName)
+	if err != nil {
+		s.lc.Errorf("Reload change-of-value resources of device %s failed, keep the current ones, error: %v", deviceName, err)
+		return previous
+	}
+	byName := make(map[string]*covResource, len(previous))
+	for _, r := range previous {
+		byName[r.request.DeviceResourceName] = r
+	}
+	for _, r := range resources {
+		if old, ok := byName[r.request.DeviceResourceName]; ok && old.edge == r.edge && old.request.Type == r.request.Type {
+			r.last, r.state, r.risingTime = old.last, old.state, old.risingTime
+		}
+	}
+	return resources
+}
+
+// pollChanges polls the resources and publishes the changed values, and all of them every integrity interval.
+// The resources are reloaded from the device profile on each integrity cycle.
+func (s *Driver) pollChanges(ctx context.Context, deviceName string, protocols map[string]models.ProtocolProperties, resources []*covResource,
+	interval time.Duration, integrityInterval time.Duration) {
+	s.lc.Infof("Change-of-value poller of device %s started, %d resources, interval: %v", deviceName, len(resources), interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastIntegrity := time.Now()
+	for {
+		integrity := integrityInterval > 0 && time.Since(lastIntegrity) >= integrityInterval
+		if integrity {
+			lastIntegrity = time.Now()
+			resources = s.reloadCOVResources(deviceName, resources)
+		}
+		if len(resources) > 0 {
+			s.pollCOVResources(ctx, deviceName, protocols, resources, integrity)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.lc.Infof("Change-of-value poller of device %s stopped", deviceName)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollCOVResources reads the resources once and publishes the changed values and the edges of the bits
+func (s *Driver) pollCOVResources(ctx context.Context, deviceName string, protocols map[string]models.ProtocolProperties, resources []*covResource, integrity bool) {
+	reqs := make([]sdkModel.CommandRequest, len(resources))
+	byName := make(map[string]*covResource, len(resources))
+	for i, r := range resources {
+		reqs[i] = r.request
+		byName[r.request.DeviceResourceName] = r
+	}
+
+	res, err := s.HandleReadCommands(deviceName, protocols, reqs)
+	if err != nil {
+		s.lc.Debugf("Change-of-value poll of device %s failed, error: %v", deviceName, err)
+		return
+	}
+	now := time.Now()
+	for _, value := range res {
+		r, ok := byName[value.DeviceResourceName]
+		if !ok {
+			continue
+		}
+		if r.edge != "" {
+			// edge resources publish the transitions only, not the integrity refresh
+			if !r.transition(value, now) {
+				continue
+			}
+		} else if !integrity && !r.changed(value) {
+			continue
+		}
+		r.last = value
+		select {
+		case s.asyncCh <- &sdkModel.AsyncValues{
+			DeviceName:    deviceName,
+			SourceName:    value.DeviceResourceName,
+			CommandValues: []*sdkModel.CommandValue{value},
+		}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// getPollIntervals returns the change-of-value poll interval and the integrity interval, zero means disabled
+func getPollIntervals(pp models.ProtocolProperties) (interval time.Duration, integrityInterval time.Duration, err error) {
+	if value, ok := pp[POLL_INTERVAL]; ok && value != "" {
+		milliseconds, err := cast.ToIntE(value)
+		if err != nil || milliseconds < 0 {
+			return 0, 0, fmt.Errorf("%s %v is not a positive integer", POLL_INTERVAL, value)
+		}
+		interval = time.Duration(milliseconds) * time.Millisecond
+	}
+	if value, ok := pp[INTEGRITY_INTERVAL]; ok && value != "" {
+		seconds, err := cast.ToIntE(value)
+		if err != nil || seconds < 0 {
+			return 0, 0, fmt.Errorf("%s %v is not a positive integer", INTEGRITY_INTERVAL, value)
+		}
+		integrityInterval = time.Duration(seconds) * time.Second
+	}
+	return interval, integrityInterval, nil
+}
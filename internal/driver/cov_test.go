@@ -0,0 +1,175 @@
+package driver
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestCOVResourceChanged(t *testing.T) {
+	newValue := func(valueType string, value any) *sdkModel.CommandValue {
+		cv, err := sdkModel.NewCommandValue("value", valueType, value)
+		if err != nil {
+			t.Fatalf("NewCommandValue() error = %v", err)
+		}
+		return cv
+	}
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		last       *sdkModel.CommandValue
+		value      *sdkModel.CommandValue
+		want       bool
+	}{
+		{name: "first value", value: newValue(common.ValueTypeInt16, int16(1)), want: true},
+		{name: "unchanged", last: newValue(common.ValueTypeInt16, int16(1)), value: newValue(common.ValueTypeInt16, int16(1))},
+		{name: "changed", last: newValue(common.ValueTypeInt16, int16(1)), value: newValue(common.ValueTypeInt16, int16(2)), want: true},
+		{name: "bool changed", last: newValue(common.ValueTypeBool, false), value: newValue(common.ValueTypeBool, true), want: true},
+		{name: "within deadband", attributes: map[string]any{DEADBAND: 0.5},
+			last: newValue(common.ValueTypeFloat32, float32(10)), value: newValue(common.ValueTypeFloat32, float32(10.4))},
+		{name: "beyond deadband", attributes: map[string]any{DEADBAND: 0.5},
+			last: newValue(common.ValueTypeFloat32, float32(10)), value: newValue(common.ValueTypeFloat32, float32(9.4)), want: true},
+		{name: "within percent deadband", attributes: map[string]any{DEADBAND_PERCENT: 5},
+			last: newValue(common.ValueTypeInt32, int32(200)), value: newValue(common.ValueTypeInt32, int32(209))},
+		{name: "beyond percent deadband", attributes: map[string]any{DEADBAND_PERCENT: 5},
+			last: newValue(common.ValueTypeInt32, int32(200)), value: newValue(common.ValueTypeInt32, int32(211)), want: true},
+		{name: "string changed", last: newValue(common.ValueTypeString, "a"), value: newValue(common.ValueTypeString, "b"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newCOVResource(models.DeviceResource{Name: "value", Attributes: tt.attributes})
+			if err != nil {
+				t.Fatalf("newCOVResource() error = %v", err)
+			}
+			r.last = tt.last
+			if got := r.changed(tt.value); got != tt.want {
+				t.Errorf("changed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newCOVResource(models.DeviceResource{Name: "value", Attributes: map[string]any{DEADBAND: -1}}); err == nil {
+		t.Errorf("newCOVResource() with a negative deadband should fail")
+	}
+}
+
+func TestPollCOVResources(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	asyncCh := make(chan *sdkModel.AsyncValues, 16)
+	s.asyncCh = asyncCh
+
+	level, _ := newCOVResource(models.DeviceResource{
+		Name:       "level",
+		Attributes: map[string]any{"NodeName": "DB1.DBW0", DEADBAND: 5},
+		Properties: models.ResourceProperties{ValueType: common.ValueTypeInt16},
+	})
+	alarm, _ := newCOVResource(models.DeviceResource{
+		Name:       "alarm",
+		Attributes: map[string]any{"NodeName": "DB1.DBX2.0"},
+		Properties: models.ResourceProperties{ValueType: common.ValueTypeBool},
+	})
+	resources := []*covResource{level, alarm}
+
+	published := func() map[string]any {
+		values := make(map[string]any)
+		for len(asyncCh) > 0 {
+			acv := <-asyncCh
+			values[acv.SourceName] = acv.CommandValues[0].Value
+		}
+		return values
+	}
+
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, false)
+	if got := published(); len(got) != 2 {
+		t.Errorf("first poll published %v, want all resources", got)
+	}
+
+	_ = server.WriteArea(s7server.AreaDB, 1, 0, binary.BigEndian.AppendUint16(nil, 3))
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, false)
+	if got := published(); len(got) != 0 {
+		t.Errorf("poll within deadband published %v", got)
+	}
+
+	_ = server.WriteArea(s7server.AreaDB, 1, 0, binary.BigEndian.AppendUint16(nil, 10))
+	_ = server.WriteBit(s7server.AreaDB, 1, 2, 0, true)
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, false)
+	if got := published(); got["level"] != int16(10) || got["alarm"] != true {
+		t.Errorf("poll after changes published %v", got)
+	}
+
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, true)
+	if got := published(); len(got) != 2 {
+		t.Errorf("integrity poll published %v, want all resources", got)
+	}
+
+	server.DropNextRequests(10)
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, false)
+	if got := published(); len(got) != 0 {
+		t.Errorf("failed poll published %v", got)
+	}
+}
+
+func TestPollChangesReloadsResources(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	asyncCh := make(chan *sdkModel.AsyncValues, 16)
+	s.asyncCh = asyncCh
+	sdk := newTestSDK(nil)
+	sdk.profile = models.DeviceProfile{Name: "profile", DeviceResources: []models.DeviceResource{{
+		Name:       "level",
+		Attributes: map[string]any{"NodeName": "DB1.DBW0", COV: true},
+		Properties: models.ResourceProperties{ValueType: common.ValueTypeInt16, ReadWrite: common.ReadWrite_R},
+	}}}
+	s.sdk = sdk
+
+	resources, err := s.getCOVResources(testDevice)
+	if err != nil || len(resources) != 1 {
+		t.Fatalf("getCOVResources() = %d resources, error = %v, want 1", len(resources), err)
+	}
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, false)
+	<-asyncCh
+
+	// a deadband added to the profile applies to the reloaded resource, the last value is kept
+	sdk.profile.DeviceResources[0].Attributes = map[string]any{"NodeName": "DB1.DBW0", COV: true, DEADBAND: 5}
+	resources = s.reloadCOVResources(testDevice, resources)
+	_ = server.WriteArea(s7server.AreaDB, 1, 0, binary.BigEndian.AppendUint16(nil, 3))
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, false)
+	if len(asyncCh) != 0 {
+		t.Errorf("poll within the reloaded deadband published %d values", len(asyncCh))
+	}
+
+	// an invalid profile keeps the current resources
+	sdk.profile.DeviceResources[0].Attributes = map[string]any{"NodeName": "DB1.DBW0", COV: true, DEADBAND: -1}
+	if reloaded := s.reloadCOVResources(testDevice, resources); len(reloaded) != 1 || reloaded[0] != resources[0] {
+		t.Errorf("reloadCOVResources() after a failed reload = %v, want the current resources", reloaded)
+	}
+}
+
+func TestPollCOVResourcesCancelled(t *testing.T) {
+	_, s, protocols := newTestServer(t)
+	s.asyncCh = make(chan *sdkModel.AsyncValues) // never received
+	level, _ := newCOVResource(models.DeviceResource{
+		Name:       "level",
+		Attributes: map[string]any{"NodeName": "DB1.DBW0"},
+		Properties: models.ResourceProperties{ValueType: common.ValueTypeInt16},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.pollCOVResources(ctx, testDevice, protocols, []*covResource{level}, true)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("pollCOVResources() is blocked on the full async channel after the cancellation")
+	}
+}
@@ -9,7 +9,9 @@
 package driver
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -43,10 +45,33 @@ var once sync.Once
 var driver *Driver
 
 type Driver struct {
+	sdk       interfaces.DeviceServiceSDK
 	lc        logger.LoggingClient
 	asyncCh   chan<- *sdkModel.AsyncValues
 	s7Clients map[string]*S7Client
-	mu        sync.Mutex
+	tasks     map[string]context.CancelFunc
+	// contexts of the background tasks, cancelled with them
+	taskContexts map[string]context.Context
+	backupDir    string
+	// result of the last block backup of the devices
+	backups map[string]*backupResult
+	alarms  map[string]*alarmState
+	// symbols of the devices with a 'SymbolFile'
+	symbolTables map[string]symbolTable
+	// read plans of the commands of the devices
+	readPlans map[string]map[string]*readPlan
+	// the 'S7Config' section of the configuration and its parsed 'Writable' section
+	serviceConfig *ServiceConfig
+	config        *driverConfig
+	// metrics of the devices
+	metrics map[string]*deviceMetrics
+	// connection states of the devices
+	connStates map[string]*connectionState
+	// devices whose AdminState is LOCKED
+	locked map[string]bool
+	// protocols and profiles of the connected devices, see deviceKey
+	deviceKeys map[string]string
+	mu         sync.Mutex
 }
 
 func NewProtocolDriver() interfaces.ProtocolDriver {
@@ -81,19 +106,58 @@ type DBInfo struct {
 // Initialize performs protocol-specific initialization for the device
 // service.
 func (s *Driver) Initialize(sdk interfaces.DeviceServiceSDK) error {
+	s.sdk = sdk
 	s.lc = sdk.LoggingClient()
 	s.asyncCh = sdk.AsyncValuesChannel()
 	s.s7Clients = make(map[string]*S7Client)
+	s.tasks = make(map[string]context.CancelFunc)
+	s.symbolTables = make(map[string]symbolTable)
+	s.readPlans = make(map[string]map[string]*readPlan)
+	s.metrics = make(map[string]*deviceMetrics)
+	s.connStates = make(map[string]*connectionState)
+	s.locked = make(map[string]bool)
+	s.deviceKeys = make(map[string]string)
+	s.backupDir = sdk.DriverConfigs()[BACKUP_DIR]
+	if s.backupDir == "" {
+		s.backupDir = defaultBackupDir
+	}
+
+	s.serviceConfig = &ServiceConfig{}
+	if err := sdk.LoadCustomConfig(s.serviceConfig, CUSTOM_CONFIG); err != nil {
+		s.lc.Errorf("unable to load '%s' custom configuration, error: %v", CUSTOM_CONFIG, err)
+		return err
+	}
+	config, err := newDriverConfig(s.serviceConfig.S7Config.Writable)
+	if err != nil {
+		s.lc.Errorf("Invalid '%s' custom configuration, error: %v", CUSTOM_CONFIG, err)
+		return err
+	}
+	s.config = config
+	if err = sdk.ListenForCustomConfigChanges(&s.serviceConfig.S7Config.Writable, CUSTOM_CONFIG_WRITABLE, s.updateWritableConfig); err != nil {
+		s.lc.Errorf("unable to listen for changes of '%s' custom configuration, error: %v", CUSTOM_CONFIG_WRITABLE, err)
+		return err
+	}
 
 	// initialize the all devices connection in the service started
 	for _, device := range sdk.Devices() {
+		if err := s.loadSymbols(device.Name, device.Protocols); err != nil {
+			continue
+		}
+		// locked devices are connected when they are unlocked
+		if device.AdminState == models.Locked {
+			s.lockDevice(device.Name)
+			continue
+		}
+		s.loadConnectionState(device.Name, device.OperatingState)
 		s7Client := s.NewS7Client(device.Name, device.Protocols)
 		if s7Client == nil {
 			s.lc.Errorf("failed to initialize S7 client for '%s' device, skipping this device.", device.Name)
 			continue
 		}
 		s.s7Clients[device.Name] = s7Client
+		s.setDeviceKey(device.Name, s.deviceKey(device.Name, device.Protocols))
 		s.lc.Debugf("S7Client connected for device: %s", device.Name)
+		s.startDeviceTasks(device.Name, device.Protocols)
 	}
 
 	return nil
@@ -102,138 +166,90 @@ func (s *Driver) Initialize(sdk interfaces.DeviceServiceSDK) error {
 // HandleReadCommands triggers a protocol Read operation for the specified device.
 func (s *Driver) HandleReadCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []sdkModel.CommandRequest) (res []*sdkModel.CommandValue, err error) {
 	s.lc.Debugf("Driver.HandleReadCommands: protocols: %v, resource: %v, attributes: %v", protocols, reqs[0].DeviceResourceName, reqs[0].Attributes)
+	if err = s.checkUnlocked(deviceName); err != nil {
+		return nil, err
+	}
 
-	// assume the max batch size is 16, must be less than 20
-	var batch_size = 16
-
-	var reqs_len = len(reqs)
-	var s7DataItems = []gos7.S7DataItem{}
-
-	var s7_errors = make([]string, reqs_len)
-	// two-dimensional array for handle S7DataItems
-	var dataset = make([][]byte, reqs_len)
-	for i := range dataset {
-		dataset[i] = make([]byte, 4) // 4 bytes
+	// SZL, block backup and alarm resources are read by their own requests, not by AGReadMulti
+	reqs, szlReqs := splitRequests(reqs, SZL_ID)
+	if len(szlReqs) > 0 {
+		res = s.readSZLCommands(deviceName, protocols, szlReqs)
 	}
+	reqs, backupReqs := splitRequests(reqs, BLOCK_BACKUP)
+	if len(backupReqs) > 0 {
+		backupRes, err := s.readBackupCommands(deviceName, backupReqs)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, backupRes...)
+	}
+	reqs, alarmReqs := splitRequests(reqs, ALARM_WORDS)
+	if len(alarmReqs) > 0 {
+		res = append(res, s.readAlarmCommands(deviceName, protocols, alarmReqs)...)
+	}
+	// the connection state is read after the other resources, it includes their result
+	reqs, stateReqs := splitResource(reqs, CONNECTION_STATE_RESOURCE)
 
 	// Get S7 device connection information, each Device has its own connection.
 	s7Client := s.getS7Client(deviceName, protocols)
 
-	// assemble s7DataItems, get items, fetch data
-
-	var times = int(reqs_len / batch_size)
-	var remains = reqs_len % batch_size
-	var tmp_reqs []sdkModel.CommandRequest
+	// V memory addresses of LOGO! and S7-200 depend on the device family
+	family, err := getFamily(protocols[Protocol])
+	if err != nil {
+		s.lc.Errorf("read reqs %+v failed, error: %v", reqs, err)
+		return nil, err
+	}
 
-outloop:
-	for j := 0; j <= times; j++ {
+	if len(reqs) > 0 {
+		// the command is parsed, joined in ranges and batched once, then read by its cached plan
+		plan := s.getReadPlan(deviceName, family, getPDULength(s7Client), reqs)
+		plan.mu.Lock()
+		defer plan.mu.Unlock()
+		s.read(deviceName, protocols, plan)
+		s.lc.Debugf("Read S7DataItems: %+v", plan.s7Items)
+
+		// read results from the ranges of the plan
+		for i, req := range reqs {
+			item := plan.items[i]
+			if item.err != nil {
+				s.lc.Errorf("invalid resource %s, error: %v", req.DeviceResourceName, item.err)
+				continue
+			}
+			if item.s7Err != "" {
+				s.lc.Errorf("S7 Client AGRead req %+v failed,error: %s", req, item.s7Err)
+				continue
+			}
 
-		// 1. init array
-		s7DataItems = s7DataItems[:0] // clear array
-		tmp_reqs = tmp_reqs[:0]       // clear array
-		if j >= times {
-			if remains > 0 {
-				tmp_reqs = reqs[times*batch_size : times*batch_size+remains]
+			buffer := item.value()
+			reorderBytes(buffer[:item.size], item.order)
+			var value any
+			var quality string
+			if item.sc != nil {
+				value, quality, err = item.sc.read(buffer, item.wordLength)
+			} else if item.encoding != "" {
+				value, err = decodeValue(item.encoding, buffer[:item.size])
 			} else {
-				_ = tmp_reqs
-				break outloop // load complete, exit loop
+				value, err = getCommandValueType(buffer, req.Type)
 			}
-		} else {
-			tmp_reqs = reqs[j*batch_size : j*batch_size+batch_size]
-		}
-
-		// 2. get resources from reqs append to items
-		var count int
-		for i, req := range tmp_reqs {
-
-			nodename := cast.ToString(req.Attributes["NodeName"])
-			dbInfo, err := s.getDBInfo(nodename)
 			if err != nil {
-				count++
-				s.lc.Errorf("convert nodeName to dbInfo failed,err =%v", err)
-				var nilS7DataItem = gos7.S7DataItem{
-					Area:     0,
-					WordLen:  0,
-					DBNumber: 0,
-					Start:    0,
-					Amount:   0,
-					Data:     dataset[j*batch_size+i],
-				}
-				s7DataItems = append(s7DataItems, nilS7DataItem)
+				s.lc.Errorf("getCommandValueType error: %s", err)
 				continue
 			}
-			var s7DataItem = gos7.S7DataItem{
-				Area:     dbInfo.Area,
-				WordLen:  dbInfo.WordLength,
-				DBNumber: dbInfo.DBNumber,
-				Start:    dbInfo.Start,
-				Amount:   dbInfo.Amount,
-				Data:     dataset[j*batch_size+i],
-			}
-			s7DataItems = append(s7DataItems, s7DataItem)
-		}
-		s.lc.Debugf("Read from S7DataItems: %+v", s7DataItems)
-		if count == len(tmp_reqs) {
-			s.lc.Errorf("commandRequest %+v is invalid", tmp_reqs)
-			continue
-		}
 
-		// 3. use AGReadMulti api to get values from S7 device, if error, try 3 times
-		retrytimes := 3
-		for {
-			err = s7Client.Client.AGReadMulti(s7DataItems, len(s7DataItems))
+			result, err := getCommandValue(req, value)
 			if err != nil {
-				s.lc.Errorf("AGReadMulti Error: %s, reconnecting...", err)
-				s.mu.Lock()
-				s.s7Clients[deviceName] = nil
-				s.mu.Unlock()
-				s7Client = s.getS7Client(deviceName, protocols)
-			} else {
-				s.lc.Debugf("AGReadMulti read from 'dataset': ", dataset)
-				break
+				s.lc.Errorf("getCommandValue error: %v", err)
+				continue
 			}
-
-			retrytimes--
-			if retrytimes == 0 {
-				break
+			if quality != "" {
+				result.Tags = map[string]string{qualityTag: quality}
 			}
 
+			res = append(res, result)
 		}
-		//4. use s7_errors to record the abnormal error messages of all read points
-		for i, s7DataItem := range s7DataItems {
-			if s7_error := s7DataItem.Error; s7_error != "" {
-				s.lc.Errorf("s7DataItem:%+v,error: %s", s7DataItem, s7_error)
-				s7_errors[j*batch_size+i] = s7_error
-			}
-		}
-
 	}
-	// end assemble s7DataItems
-	s.lc.Debugf("Read S7DataItems: %+v", s7DataItems)
-	// read results from the dataset of s7DataItems
-	for i, req := range reqs {
-
-		var result *sdkModel.CommandValue
-		var value any
-
-		if s7_error := s7_errors[i]; s7_error != "" {
-			s.lc.Errorf("S7 Client AGRead req %+v failed,error: %s", req, s7_error)
-			continue
-		}
-
-		value, err := getCommandValueType(dataset[i], req.Type)
-		if err != nil {
-			s.lc.Errorf("getCommandValueType error: %s", err)
-			continue
-		}
-
-		result, err = getCommandValue(req, value)
-		if err != nil {
-			s.lc.Errorf("getCommandValue error: %v", err)
-			continue
-		}
-
-		res = append(res, result)
+	if len(stateReqs) > 0 {
+		res = append(res, s.readConnectionState(deviceName, stateReqs)...)
 	}
 	if len(res) == 0 {
 		s.lc.Errorf("read reqs %+v failed", reqs)
@@ -253,9 +269,40 @@ func (s *Driver) HandleWriteCommands(deviceName string, protocols map[string]mod
 	s.lc.Debugf("Driver.HandleWriteCommands: protocols: %v, resource: %v, parameters: %v", protocols, reqs[0].DeviceResourceName, params)
 
 	var err error
+	if err = s.checkUnlocked(deviceName); err != nil {
+		return err
+	}
 
-	// assume the max batch size is 16, must be less than 20
-	var batch_size = 16
+	// block backup resources are triggered by writing the block selection, an empty one takes the selection of the attribute
+	var dataReqs []sdkModel.CommandRequest
+	var dataParams []*sdkModel.CommandValue
+	for i, req := range reqs {
+		if _, ok := req.Attributes[BLOCK_BACKUP]; !ok {
+			dataReqs = append(dataReqs, req)
+			dataParams = append(dataParams, params[i])
+			continue
+		}
+		selection, err := params[i].StringValue()
+		if err != nil {
+			s.lc.Errorf("Block selection of %s should be a string, error: %v", req.DeviceResourceName, err)
+			return err
+		}
+		if strings.TrimSpace(selection) == "" {
+			selection = cast.ToString(req.Attributes[BLOCK_BACKUP])
+		}
+		if _, err = s.backupBlocks(deviceName, protocols, selection); err != nil {
+			s.lc.Errorf("Block backup of device %s failed, error: %v", deviceName, err)
+			return err
+		}
+	}
+	if len(dataReqs) == 0 {
+		return nil
+	}
+	reqs, params = dataReqs, dataParams
+
+	// the max batch size is configured by 'BatchSize', at most 20
+	config := s.getConfig()
+	var batch_size = config.batchSize
 
 	var reqs_len = len(reqs)
 	var s7DataItems = []gos7.S7DataItem{}
@@ -265,7 +312,14 @@ func (s *Driver) HandleWriteCommands(deviceName string, protocols map[string]mod
 	// two-dimensional array for handle S7DataItems
 	var dataset = make([][]byte, reqs_len)
 	for i := range dataset {
-		dataset[i] = make([]byte, 4) // 4 bytes
+		dataset[i] = make([]byte, 8) // 8 bytes, the 64-bit values
+	}
+
+	// V memory addresses of LOGO! and S7-200 depend on the device family
+	family, err := getFamily(protocols[Protocol])
+	if err != nil {
+		s.lc.Errorf("write reqs %+v failed, error: %v", reqs, err)
+		return err
 	}
 
 	// transfer command values to S7DataItems
@@ -275,7 +329,7 @@ func (s *Driver) HandleWriteCommands(deviceName string, protocols map[string]mod
 		s.lc.Debugf("S7Driver.HandleWriteCommands: protocols: %v, resource: %v, parameters: %v, attributes: %v", protocols, req.DeviceResourceName, params[i], req.Attributes)
 
 		var nodeName = cast.ToString(req.Attributes["NodeName"])
-		var dbInfo, err = s.getDBInfo(nodeName)
+		var dbInfo, err = s.getFamilyDBInfo(deviceName, family, nodeName)
 		if err != nil {
 			count++
 			s.lc.Errorf("convert nodeName %v to dbInfo failed,err =%v", nodeName, err)
@@ -293,7 +347,34 @@ func (s *Driver) HandleWriteCommands(deviceName string, protocols map[string]mod
 		if err != nil {
 			s.lc.Errorf("newCommandValue error: %s", err)
 		}
+		// scaled resources are written as the raw counts
+		sc, err := getScaling(req.Attributes, req.Type)
+		if err != nil {
+			s.lc.Errorf("invalid scaling of resource %s, error: %v", req.DeviceResourceName, err)
+			return fmt.Errorf("invalid scaling of resource %s, error: %v", req.DeviceResourceName, err)
+		} else if sc != nil {
+			reading = sc.write(cast.ToFloat64(reading), dbInfo.WordLength)
+		}
+		// BCD and S5TIME resources are written encoded
+		encoding, err := getEncoding(req.Attributes, req.Type)
+		if err != nil {
+			s.lc.Errorf("invalid encoding of resource %s, error: %v", req.DeviceResourceName, err)
+			return fmt.Errorf("invalid encoding of resource %s, error: %v", req.DeviceResourceName, err)
+		}
+		rawType := rawValueType(req.Type, sc != nil, encoding)
+		if encoding != "" {
+			if reading, err = encodeValue(encoding, cast.ToInt64(reading), valueSize(dbInfo.WordLength, rawType)); err != nil {
+				s.lc.Errorf("encode %s of resource %s failed, error: %v", encoding, req.DeviceResourceName, err)
+				return err
+			}
+		}
 		helper.SetValueAt(dataset[i], 0, reading)
+		order, err := getByteOrder(req.Attributes)
+		if err != nil {
+			s.lc.Errorf("invalid byte order of resource %s, error: %v", req.DeviceResourceName, err)
+			return fmt.Errorf("invalid byte order of resource %s, error: %v", req.DeviceResourceName, err)
+		}
+		reorderBytes(dataset[i][:valueSize(dbInfo.WordLength, rawType)], order)
 
 		// create gos7 DataItem
 		var s7DataItem = gos7.S7DataItem{
@@ -304,6 +385,7 @@ func (s *Driver) HandleWriteCommands(deviceName string, protocols map[string]mod
 			Amount:   dbInfo.Amount,
 			Data:     dataset[i],
 		}
+		resizeItem(&s7DataItem, rawType)
 		s7DataItems = append(s7DataItems, s7DataItem)
 
 	}
@@ -335,16 +417,15 @@ outloop:
 			tmp_s7DateItems = s7DataItems[j*batch_size : j*batch_size+batch_size]
 		}
 
-		// write data to S7 device, if error, try 3 times
-		retrytimes := 3
+		// write data to S7 device, if error, try 'RetryCount' times
+		retrytimes := config.retryCount
 		for {
+			start := time.Now()
 			err = s7Client.Client.AGWriteMulti(tmp_s7DateItems, len(tmp_s7DateItems))
+			s.recordRequest(deviceName, true, tmp_s7DateItems, start, err)
 			if err != nil {
 				s.lc.Errorf("AGWriteMulti Error: %s, reconnecting...", err)
-				s.mu.Lock()
-				s.s7Clients[deviceName] = nil
-				s.mu.Unlock()
-				s7Client = s.getS7Client(deviceName, protocols)
+				s7Client = s.reconnect(deviceName, protocols)
 			} else {
 				s.lc.Debugf("AGWriteMulti write from 'dataset': %s", dataset)
 				break
@@ -355,6 +436,8 @@ outloop:
 				break
 			}
 		}
+		// the connection state records the result of the batch once the retries are used up
+		s.updateConnectionState(deviceName, err)
 		// Record all errors
 		for i, tmp_s7DataItem := range tmp_s7DateItems {
 			if s7_error := tmp_s7DataItem.Error; s7_error != "" {
@@ -382,7 +465,19 @@ outloop:
 func (s *Driver) Stop(force bool) error {
 
 	s.mu.Lock()
-	s.s7Clients = nil
+	for _, cancel := range s.tasks {
+		cancel()
+	}
+	for _, c := range s.connStates {
+		if c.cancelProbe != nil {
+			c.cancelProbe()
+		}
+	}
+	s.tasks = make(map[string]context.CancelFunc)
+	s.s7Clients = make(map[string]*S7Client)
+	s.readPlans = make(map[string]map[string]*readPlan)
+	s.connStates = make(map[string]*connectionState)
+	s.locked = make(map[string]bool)
 	s.mu.Unlock()
 
 	// Then Logging Client might not be initialized
@@ -397,18 +492,34 @@ func (s *Driver) Stop(force bool) error {
 func (s *Driver) AddDevice(deviceName string, protocols map[string]models.ProtocolProperties, adminState models.AdminState) error {
 	s.lc.Debugf("a new Device is added: %s", deviceName)
 
-	s.mu.Lock()
-	s.s7Clients[deviceName] = nil
-	s.mu.Unlock()
+	s.invalidateReadPlans(deviceName)
+	if err := s.loadSymbols(deviceName, protocols); err != nil {
+		return err
+	}
+	if err := s.validateDeviceProfile(deviceName, protocols); err != nil {
+		return err
+	}
+	// a locked device releases its connection and suspends its tasks until it is unlocked
+	if adminState == models.Locked {
+		s.lockDevice(deviceName)
+		return nil
+	}
+	s.unlockDevice(deviceName)
+	s.syncConnectionState(deviceName)
+
+	s.dropS7Client(deviceName)
 	s7Client := s.getS7Client(deviceName, protocols)
 	if s7Client == nil {
 		errt := fmt.Errorf("failed to initialize S7 client for '%s' device, skipping this device", deviceName)
 		s.lc.Errorf(errt.Error())
 		return errt
 	}
+	key := s.deviceKey(deviceName, protocols)
 	s.mu.Lock()
 	s.s7Clients[deviceName] = s7Client
+	s.setDeviceKey(deviceName, key)
 	s.mu.Unlock()
+	s.startDeviceTasks(deviceName, protocols)
 	return nil
 }
 
@@ -417,6 +528,32 @@ func (s *Driver) AddDevice(deviceName string, protocols map[string]models.Protoc
 func (s *Driver) UpdateDevice(deviceName string, protocols map[string]models.ProtocolProperties, adminState models.AdminState) error {
 	s.lc.Debugf("Device %s is updated", deviceName)
 
+	s.invalidateReadPlans(deviceName)
+	if err := s.loadSymbols(deviceName, protocols); err != nil {
+		return err
+	}
+	if err := s.validateDeviceProfile(deviceName, protocols); err != nil {
+		return err
+	}
+	// a locked device releases its connection and suspends its tasks until it is unlocked
+	if adminState == models.Locked {
+		s.lockDevice(deviceName)
+		return nil
+	}
+	wasLocked := s.isLocked(deviceName)
+	s.unlockDevice(deviceName)
+	s.syncConnectionState(deviceName)
+
+	// the changes of the OperatingState, like those set by the connection state, keep the connection and the tasks
+	key := s.deviceKey(deviceName, protocols)
+	s.mu.Lock()
+	unchanged := !wasLocked && s.deviceKeys[deviceName] == key
+	s.mu.Unlock()
+	if unchanged {
+		s.lc.Debugf("Protocols and profile of device %s are unchanged, its connection and tasks are kept", deviceName)
+		return nil
+	}
+
 	s7Client := s.NewS7Client(deviceName, protocols)
 	if s7Client == nil {
 		errt := fmt.Errorf("failed to initialize S7 client for '%s' device, skipping this device", deviceName)
@@ -424,8 +561,12 @@ func (s *Driver) UpdateDevice(deviceName string, protocols map[string]models.Pro
 		return errt
 	}
 	s.mu.Lock()
+	previous := s.s7Clients[deviceName]
 	s.s7Clients[deviceName] = s7Client
+	s.setDeviceKey(deviceName, key)
 	s.mu.Unlock()
+	closeS7Client(previous)
+	s.startDeviceTasks(deviceName, protocols)
 
 	return nil
 }
@@ -434,9 +575,22 @@ func (s *Driver) UpdateDevice(deviceName string, protocols map[string]models.Pro
 // when a Device associated with this Device Service is removed
 func (s *Driver) RemoveDevice(deviceName string, protocols map[string]models.ProtocolProperties) error {
 	s.lc.Debugf("Device %s is removed", deviceName)
+	s.stopDeviceTasks(deviceName)
 	s.mu.Lock()
 	delete(s.s7Clients, deviceName)
+	delete(s.symbolTables, deviceName)
+	delete(s.readPlans, deviceName)
+	delete(s.locked, deviceName)
+	delete(s.deviceKeys, deviceName)
+	delete(s.backups, deviceName)
+	for key := range s.alarms {
+		if strings.HasPrefix(key, deviceName+"/") {
+			delete(s.alarms, key)
+		}
+	}
 	s.mu.Unlock()
+	s.unregisterMetrics(deviceName)
+	s.removeConnectionState(deviceName)
 	return nil
 }
 
@@ -463,25 +617,71 @@ func (s *Driver) ValidateDevice(device models.Device) error {
 		s.lc.Errorf("Port not found or not an integer in Protocol, error: %s", errt)
 		return errt
 	}
-	_, errt = cast.ToIntE(pp["Rack"])
+	// Rack and Slot are not needed by an explicit RemoteTSAP
+	_, errt = getConnectionInfo(pp)
 	if errt != nil {
-		s.lc.Errorf("Rack not found or not an integer in Protocol, error: %s", errt)
+		s.lc.Errorf("Invalid connection configuration in Protocol, error: %s", errt)
 		return errt
 	}
-	_, errt = cast.ToIntE(pp["Slot"])
+	_, errt = cast.ToIntE(pp["Timeout"])
 	if errt != nil {
-		s.lc.Errorf("Slot not found or not an integer in Protocol, error: %s", errt)
+		s.lc.Warnf("Timeout not found or not an integer in Protocol, USE DEFAULT %v, error: %s", s.getConfig().timeout, errt)
+	}
+	_, errt = cast.ToIntE(pp["IdleTimeout"])
+	if errt != nil {
+		s.lc.Warnf("IdleTimeout not found or not an integer in Protocol, USE DEFAULT %v, error: %s", s.getConfig().idleTimeout, errt)
+	}
+	_, errt = getFamily(pp)
+	if errt != nil {
+		s.lc.Errorf("Invalid device family in Protocol, error: %s", errt)
 		return errt
 	}
-	_, errt = cast.ToIntE(pp["Timeout"])
+	_, errt = cast.ToStringE(pp[PASSWORD])
 	if errt != nil {
-		s.lc.Errorf("Timeout not found or not an integer in Protocol, USE DEFAULT 30s error: %s", errt)
-		pp["Timeout"] = 30
+		s.lc.Errorf("Password should be the name of a secret, error: %s", errt)
+		return errt
 	}
-	_, errt = cast.ToIntE(pp["IdleTimeout"])
+	_, errt = getBlockMonitorInterval(pp)
+	if errt != nil {
+		s.lc.Errorf("Invalid block monitor configuration, error: %s", errt)
+		return errt
+	}
+	_, _, errt = getPollIntervals(pp)
+	if errt != nil {
+		s.lc.Errorf("Invalid change-of-value poll configuration, error: %s", errt)
+		return errt
+	}
+	_, errt = getKeepAliveInterval(pp)
 	if errt != nil {
-		s.lc.Errorf("IdleTimeout not found or not an ingeger in Protocol, USE DEFAULT 30s, error: %s", errt)
-		pp["IdleTimeout"] = 30
+		s.lc.Errorf("Invalid keepalive configuration, error: %s", errt)
+		return errt
+	}
+	_, errt = getHeartbeatConfig(pp)
+	if errt != nil {
+		s.lc.Errorf("Invalid heartbeat configuration, error: %s", errt)
+		return errt
+	}
+	var table symbolTable
+	if value := cast.ToString(pp[SYMBOL_FILE]); value != "" {
+		table, errt = loadSymbolFile(value)
+		if errt != nil {
+			s.lc.Errorf("Invalid symbol file, error: %s", errt)
+			return errt
+		}
+	}
+	// the profiles of the devices of the service are cached, the devices of the other profiles are validated when they are added
+	if s.sdk != nil {
+		family, _ := getFamily(pp)
+		for _, profile := range s.sdk.DeviceProfiles() {
+			if profile.Name != device.ProfileName {
+				continue
+			}
+			errt = validateResources(profile.DeviceResources, family, table)
+			if errt != nil {
+				s.lc.Errorf("Invalid resources of profile %s, error: %s", profile.Name, errt)
+				return errt
+			}
+		}
 	}
 
 	return nil
@@ -494,41 +694,118 @@ func (s *Driver) NewS7Client(deviceName string, protocol map[string]models.Proto
 
 	host, _ := cast.ToStringE(pp["Host"])
 	port, _ := cast.ToStringE(pp["Port"])
-	rack, _ := cast.ToIntE(pp["Rack"])
-	slot, _ := cast.ToIntE(pp["Slot"])
 	timeout, _ := cast.ToIntE(pp["Timeout"])
 	idletimeout, _ := cast.ToIntE(pp["IdleTimeout"])
 
+	// connection type and TSAPs, from 'Rack'/'Slot' or explicit 'RemoteTSAP'
+	connInfo, err := getConnectionInfo(pp)
+	if err != nil {
+		s.lc.Errorf("Invalid connection configuration of %s, error: %s", deviceName, err)
+		return nil
+	}
+
 	// create handler: PLC tcp client
-	handler := gos7.NewTCPClientHandler(host+":"+port, rack, slot)
-	if handler == nil {
-		s.lc.Errorf("Cant not create NewTCPClientHandler: %s", handler)
+	handler, err := newTCPClientHandler(host+":"+port, connInfo)
+	if err != nil {
+		s.lc.Errorf("Cant not create NewTCPClientHandler: %s", err)
 		return nil
 	}
 	s.lc.Debugf("New TCP Client: %s", handler)
 
-	// handler connect timeout from 'Timeout'
-	handler.Timeout = time.Duration(timeout) * time.Second
+	// handler connect timeout from 'Timeout', or the configured default
+	config := s.getConfig()
+	handler.Timeout = config.timeout
+	if timeout > 0 {
+		handler.Timeout = time.Duration(timeout) * time.Second
+	}
+
+	// handler connect idle timeout from 'IdleTimeout', or the configured default
+	handler.IdleTimeout = config.idleTimeout
+	if idletimeout > 0 {
+		handler.IdleTimeout = time.Duration(idletimeout) * time.Second
+	}
 
-	// handler connect idle timeout from 'IdleTimeout'
-	handler.IdleTimeout = time.Duration(idletimeout) * time.Second
+	// TCP keepalive of the keepalive interval
+	if interval, _ := getKeepAliveInterval(pp); interval > 0 {
+		handler.KeepAlive = interval
+	}
 
 	// connect to S7
-	err := handler.Connect()
+	err = handler.Connect()
 	if err != nil {
 		s.lc.Errorf("Can't handler S7 Connect: %s, error: %s", deviceName, err)
 		// return nil
 	}
+	s.recordConnection(deviceName, err == nil)
 
 	s7client := gos7.NewClient(handler)
 	client := &S7Client{
 		DeviceName: deviceName,
 		Client:     s7client,
+		Handler:    handler,
+	}
+
+	// protected CPUs need the session password after every connect
+	if secretName := cast.ToString(pp[PASSWORD]); err == nil && secretName != "" {
+		password, err := s.getSessionPassword(secretName)
+		if err != nil {
+			s.lc.Errorf("Can't get S7 session password of %s, error: %s", deviceName, err)
+		} else if err = s7client.SetSessionPassword(password); err != nil {
+			s.lc.Errorf("Can't set S7 session password of %s, error: %s", deviceName, err)
+		}
 	}
 	return client
 
 }
 
+// Get the session password from the secret named by the 'Password' protocol property
+func (s *Driver) getSessionPassword(secretName string) (string, error) {
+	if s.sdk == nil {
+		return "", fmt.Errorf("secret provider is not available")
+	}
+	secrets, err := s.sdk.SecretProvider().GetSecret(secretName, SECRET_PASSWORD_KEY)
+	if err != nil {
+		return "", err
+	}
+	password := secrets[SECRET_PASSWORD_KEY]
+	if password == "" || len(password) > 8 {
+		return "", fmt.Errorf("password of secret %s should have 1 to 8 characters", secretName)
+	}
+	return password, nil
+}
+
+// dropS7Client closes and forgets the client of a device, the next request connects it again
+func (s *Driver) dropS7Client(deviceName string) {
+	s.mu.Lock()
+	s7Client := s.s7Clients[deviceName]
+	s.s7Clients[deviceName] = nil
+	s.mu.Unlock()
+	closeS7Client(s7Client)
+}
+
+// deviceKey identifies the protocols and the profile of a device, an update changing neither of them,
+// like a change of the OperatingState, keeps its connection and its tasks
+func (s *Driver) deviceKey(deviceName string, protocols map[string]models.ProtocolProperties) string {
+	key := fmt.Sprint(protocols)
+	if s.sdk == nil {
+		return key
+	}
+	if device, err := s.sdk.GetDeviceByName(deviceName); err == nil {
+		if profile, err := s.sdk.GetProfileByName(device.ProfileName); err == nil {
+			key += fmt.Sprint(profile.Name, profile.DeviceResources, profile.DeviceCommands)
+		}
+	}
+	return key
+}
+
+// setDeviceKey records the protocols and the profile of a connected device, the caller holds the lock
+func (s *Driver) setDeviceKey(deviceName string, key string) {
+	if s.deviceKeys == nil {
+		s.deviceKeys = make(map[string]string)
+	}
+	s.deviceKeys[deviceName] = key
+}
+
 // Get S7Client by 'DeviceName'
 func (s *Driver) getS7Client(deviceName string, protocols map[string]models.ProtocolProperties) *S7Client {
 	s.mu.Lock()
@@ -549,13 +826,26 @@ func (s *Driver) getS7Client(deviceName string, protocols map[string]models.Prot
 
 // transfer DBstring to DBInfo
 func (s *Driver) getDBInfo(variable string) (dbInfo *DBInfo, err error) {
+	dbInfo, err = ParseAddress(variable)
+	if err != nil {
+		s.lc.Errorf("parse NodeName %s failed, error: %v", variable, err)
+		return nil, err
+	}
+	return dbInfo, nil
+}
+
+// ioAddressPattern matches the inputs, outputs and merkers in English (I/Q) or German (E/A) mnemonics,
+// like I4.0, QB2, MW10 or ED8
+var ioAddressPattern = regexp.MustCompile(`^([IEQAM])([XBWD]?)(\d+)(?:\.(\d+))?$`)
+
+// ParseAddress transfers an S7 address, like DB2.DBX1.0, DB2.DBD26, I4.0, QW2 or MB10, to DBInfo
+func ParseAddress(variable string) (dbInfo *DBInfo, err error) {
 
 	// varibale sample: DB2.DBX1.0 / DB2.DBD26 / DB2.DBD826
 	variable = strings.ToUpper(variable)             //upper
 	variable = strings.ReplaceAll(variable, " ", "") //remove spaces
 
 	if variable == "" {
-		s.lc.Errorf("input [NodeName] variable is empty, variable should be S7 syntax")
 		return nil, fmt.Errorf("input [NodeName] variable is empty, variable should be S7 syntax")
 	}
 
@@ -567,17 +857,7 @@ func (s *Driver) getDBInfo(variable string) (dbInfo *DBInfo, err error) {
 	var dbArray []string
 
 	//var area, dbNumber, start, amount, wordLen int
-	switch valueArea := variable[0:2]; valueArea {
-	case "EB": //input byte
-	case "EW": //input word
-	case "ED": //Input double-word
-	case "AB": //Output byte
-	case "AW": //Output word
-	case "AD": //Output double-word
-	case "MB": //Memory byte
-	case "MW": //Memory word
-	case "MD": //Memory double-word
-	case "DB": //Data Block
+	if len(variable) > 1 && variable[0:2] == "DB" { //Data Block
 		// Area ID
 		// s7areape = 0x81 //process inputs
 		// s7areapa = 0x82 //process outputs
@@ -588,18 +868,15 @@ func (s *Driver) getDBInfo(variable string) (dbInfo *DBInfo, err error) {
 		area = 0x84
 		amount = 1
 		dbArray = strings.Split(variable, ".")
-		if len(dbArray) < 2 {
-			s.lc.Errorf("Db Area read variable should not be empty")
+		if len(dbArray) < 2 || len(dbArray[1]) < 3 {
 			return nil, fmt.Errorf("DB variable %+v is invalid", variable)
 		}
 		dbNo, err = strconv.ParseInt(string(string(dbArray[0])[2:]), 10, 16)
 		if err != nil {
-			s.lc.Errorf("convert dbNo of %+v to int failed.err:%v", variable, err)
 			return nil, fmt.Errorf("convert dbNo of %+v to int failed.err:%v", variable, err)
 		}
 		dbIndex, err = strconv.ParseInt(string(string(dbArray[1])[3:]), 10, 16)
 		if err != nil {
-			s.lc.Errorf("convert dbIndex of %+v to int failed.err:%v", variable, err)
 			return nil, fmt.Errorf("convert dbIndex of %+v to int failed.err:%v", variable, err)
 		}
 
@@ -609,13 +886,11 @@ func (s *Driver) getDBInfo(variable string) (dbInfo *DBInfo, err error) {
 		case "DBX": //bit
 			wordLen = s7wlbit
 			if length := len(dbArray); length != 3 {
-				s.lc.Errorf("The point address of %+v is incorrect", variable)
 				return nil, fmt.Errorf("the point address of %+v is incorrect", variable)
 			}
 			// DBIndex = dbIndex + dbBit (DBX12.5 = 12<<3 + 5 = 96+5 = 101 = 0x65)
 			dbBit, err := strconv.ParseInt(string(string(dbArray[2])), 10, 16)
-			if err != nil {
-				s.lc.Errorf("convert dbBit of %+v to int failed.err:%v", variable, err)
+			if err != nil || dbBit < 0 || dbBit > 7 {
 				return nil, fmt.Errorf("convert dbBit of %+v to int failed.err:%v", variable, err)
 			}
 			dbIndex = dbIndex<<3 + dbBit
@@ -628,30 +903,47 @@ func (s *Driver) getDBInfo(variable string) (dbInfo *DBInfo, err error) {
 			wordLen = s7wlreal
 			// amount = 4
 		default:
-			s.lc.Errorf("error when parsing dbtype")
-			return nil, fmt.Errorf("error when parsing dbtype")
+			return nil, fmt.Errorf("error when parsing dbtype of %+v", variable)
 		}
-	default:
-		switch otherArea := variable[0:1]; otherArea {
-		case "E":
-		case "I": //input
-		case "A":
-		case "0": //output
+		if wordLen != s7wlbit && len(dbArray) != 2 {
+			return nil, fmt.Errorf("the point address of %+v is incorrect", variable)
+		}
+	} else {
+		match := ioAddressPattern.FindStringSubmatch(variable)
+		if match == nil {
+			return nil, fmt.Errorf("error when parsing db area of %+v, timers and counters are not supported", variable)
+		}
+		switch match[1] {
+		case "I", "E": //input
+			area = 0x81
+		case "Q", "A": //output
+			area = 0x82
 		case "M": //memory
-		case "T": //timer
-			return
-		case "Z":
-		case "C": //counter
-			return
-		default:
-			s.lc.Errorf("error when parsing db area")
-			return nil, fmt.Errorf("error when parsing db area")
+			area = 0x83
+		}
+		amount = 1
+		dbArray = strings.Split(variable, ".")
+		dbIndex, err = strconv.ParseInt(match[3], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("convert index of %+v to int failed.err:%v", variable, err)
+		}
+		switch size, bit := match[2], match[4]; {
+		case (size == "" || size == "X") && bit != "":
+			wordLen = s7wlbit
+			dbBit, _ := strconv.ParseInt(bit, 10, 16)
+			if dbBit > 7 {
+				return nil, fmt.Errorf("the bit of %+v should be 0 to 7", variable)
+			}
+			dbIndex = dbIndex<<3 + dbBit
+		case bit != "" || size == "" || size == "X":
+			return nil, fmt.Errorf("the point address of %+v is incorrect", variable)
+		case size == "B":
+			wordLen = s7wlbyte
+		case size == "W":
+			wordLen = s7wlword
+		case size == "D":
+			wordLen = s7wlreal
 		}
-
-	}
-	if err != nil {
-		s.lc.Errorf("convert %+v to int failed.err:%v", variable, err)
-		return nil, fmt.Errorf("convert %+v to int failed.err:%v", variable, err)
 	}
 	return &DBInfo{
 		Area:       area,
@@ -61,6 +61,66 @@ func TestDriver_getDBInfo(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:   "valid address-I4.1",
+			fields: &driver,
+			args:   args{variable: "I4.1"},
+			wantDbInfo: &DBInfo{
+				Area:       0x81,
+				Start:      33,
+				Amount:     1,
+				WordLength: s7wlbit,
+				DBArray:    []string{"I4", "1"},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "valid address-AW2",
+			fields: &driver,
+			args:   args{variable: "AW2"},
+			wantDbInfo: &DBInfo{
+				Area:       0x82,
+				Start:      2,
+				Amount:     1,
+				WordLength: s7wlword,
+				DBArray:    []string{"AW2"},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "valid address-MD10",
+			fields: &driver,
+			args:   args{variable: "MD10"},
+			wantDbInfo: &DBInfo{
+				Area:       0x83,
+				Start:      10,
+				Amount:     1,
+				WordLength: s7wlreal,
+				DBArray:    []string{"MD10"},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "invalid address-QW2.1",
+			fields:     &driver,
+			args:       args{variable: "QW2.1"},
+			wantDbInfo: nil,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid address-M10",
+			fields:     &driver,
+			args:       args{variable: "M10"},
+			wantDbInfo: nil,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid address-T1",
+			fields:     &driver,
+			args:       args{variable: "T1"},
+			wantDbInfo: nil,
+			wantErr:    true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
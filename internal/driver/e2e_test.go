@@ -0,0 +1,266 @@
+package driver
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+const testDevice = "S7-Simulator"
+
+// newTestServer starts a S7 server stand-in with DB1 and a driver connecting to it
+func newTestServer(t *testing.T) (*s7server.Server, *Driver, map[string]models.ProtocolProperties) {
+	t.Helper()
+	server := s7server.NewServer()
+	server.SetDB(1, make([]byte, 256))
+	if err := server.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(server.Stop)
+
+	host, port, _ := net.SplitHostPort(server.Addr())
+	protocols := map[string]models.ProtocolProperties{
+		Protocol: {
+			HOST:          host,
+			PORT:          port,
+			RACK:          "0",
+			SLOT:          "1",
+			"Timeout":     "1",
+			"IdleTimeout": "30",
+		},
+	}
+
+	driver = &Driver{
+		lc:        logger.NewClient("S7", "Error"),
+		s7Clients: make(map[string]*S7Client),
+		tasks:     make(map[string]context.CancelFunc),
+	}
+	t.Cleanup(func() { _ = driver.Stop(true) })
+	return server, driver, protocols
+}
+
+func newTestRequest(name string, nodeName string, valueType string) sdkModel.CommandRequest {
+	return sdkModel.CommandRequest{
+		DeviceResourceName: name,
+		Attributes:         map[string]any{"NodeName": nodeName},
+		Type:               valueType,
+	}
+}
+
+func readValues(t *testing.T, s *Driver, protocols map[string]models.ProtocolProperties, reqs []sdkModel.CommandRequest) map[string]any {
+	t.Helper()
+	res, err := s.HandleReadCommands(testDevice, protocols, reqs)
+	if err != nil {
+		t.Fatalf("HandleReadCommands() error = %v", err)
+	}
+	values := make(map[string]any)
+	for _, cv := range res {
+		values[cv.DeviceResourceName] = cv.Value
+	}
+	return values
+}
+
+func TestE2E_ReadWrite(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+
+	db := make([]byte, 16)
+	db[0] = 0x04 // DBX0.2
+	db[1] = 0xA5
+	binary.BigEndian.PutUint16(db[2:], uint16(0xFF85)) // -123
+	binary.BigEndian.PutUint32(db[4:], math.Float32bits(12.5))
+	binary.BigEndian.PutUint32(db[8:], 123456)
+	if err := server.WriteArea(s7server.AreaDB, 1, 0, db); err != nil {
+		t.Fatalf("WriteArea() error = %v", err)
+	}
+
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("bit0", "DB1.DBX0.0", common.ValueTypeBool),
+		newTestRequest("bit2", "DB1.DBX0.2", common.ValueTypeBool),
+		newTestRequest("byte", "DB1.DBB1", common.ValueTypeUint8),
+		newTestRequest("int", "DB1.DBW2", common.ValueTypeInt16),
+		newTestRequest("real", "DB1.DBD4", common.ValueTypeFloat32),
+		newTestRequest("dint", "DB1.DBD8", common.ValueTypeInt32),
+	}
+	want := map[string]any{
+		"bit0": false,
+		"bit2": true,
+		"byte": uint8(0xA5),
+		"int":  int16(-123),
+		"real": float32(12.5),
+		"dint": int32(123456),
+	}
+	if got := readValues(t, s, protocols, reqs); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("HandleReadCommands() = %v, want %v", got, want)
+	}
+
+	params := make([]*sdkModel.CommandValue, 0, len(reqs))
+	written := map[string]any{
+		"bit0": true,
+		"bit2": false,
+		"byte": uint8(0x5A),
+		"int":  int16(321),
+		"real": float32(-1.25),
+		"dint": int32(-7),
+	}
+	for _, req := range reqs {
+		cv, err := sdkModel.NewCommandValue(req.DeviceResourceName, req.Type, written[req.DeviceResourceName])
+		if err != nil {
+			t.Fatalf("NewCommandValue() error = %v", err)
+		}
+		params = append(params, cv)
+	}
+	if err := s.HandleWriteCommands(testDevice, protocols, reqs, params); err != nil {
+		t.Fatalf("HandleWriteCommands() error = %v", err)
+	}
+
+	data, _ := server.ReadArea(s7server.AreaDB, 1, 0, 12)
+	if data[0] != 0x01 || data[1] != 0x5A || int16(binary.BigEndian.Uint16(data[2:])) != 321 ||
+		math.Float32frombits(binary.BigEndian.Uint32(data[4:])) != -1.25 || int32(binary.BigEndian.Uint32(data[8:])) != -7 {
+		t.Errorf("written DB1 = % X", data)
+	}
+	if got := readValues(t, s, protocols, reqs); fmt.Sprint(got) != fmt.Sprint(written) {
+		t.Errorf("HandleReadCommands() after write = %v, want %v", got, written)
+	}
+}
+
+func TestE2E_IOAreas(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	server.SetArea(s7server.AreaPE, 0, []byte{0x00, 0x02})
+	server.SetArea(s7server.AreaPA, 0, make([]byte, 4))
+	server.SetArea(s7server.AreaMK, 0, binary.BigEndian.AppendUint32(make([]byte, 10), 4242))
+
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("input", "I1.1", common.ValueTypeBool),
+		newTestRequest("output", "QW2", common.ValueTypeUint16),
+		newTestRequest("merker", "MD10", common.ValueTypeInt32),
+	}
+	want := map[string]any{"input": true, "output": uint16(0), "merker": int32(4242)}
+	if got := readValues(t, s, protocols, reqs); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("HandleReadCommands() = %v, want %v", got, want)
+	}
+
+	cv, _ := sdkModel.NewCommandValue("output", common.ValueTypeUint16, uint16(0x1234))
+	if err := s.HandleWriteCommands(testDevice, protocols, reqs[1:2], []*sdkModel.CommandValue{cv}); err != nil {
+		t.Fatalf("HandleWriteCommands() error = %v", err)
+	}
+	if data, _ := server.ReadArea(s7server.AreaPA, 0, 2, 2); binary.BigEndian.Uint16(data) != 0x1234 {
+		t.Errorf("written QW2 = % X", data)
+	}
+}
+
+func TestE2E_Batches(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+
+	var reqs []sdkModel.CommandRequest
+	for i := 0; i < 40; i++ {
+		_ = server.WriteArea(s7server.AreaDB, 1, i*2, binary.BigEndian.AppendUint16(nil, uint16(i*10)))
+		reqs = append(reqs, newTestRequest(fmt.Sprintf("w%d", i), fmt.Sprintf("DB1.DBW%d", i*2), common.ValueTypeUint16))
+	}
+	values := readValues(t, s, protocols, reqs)
+	if len(values) != len(reqs) {
+		t.Fatalf("HandleReadCommands() returned %d values, want %d", len(values), len(reqs))
+	}
+	for i := 0; i < 40; i++ {
+		if got := values[fmt.Sprintf("w%d", i)]; got != uint16(i*10) {
+			t.Errorf("w%d = %v, want %d", i, got, i*10)
+		}
+	}
+}
+
+func TestE2E_ItemErrors(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	server.SetItemError(s7server.ItemAddress{Area: s7server.AreaDB, DB: 1, Start: 2}, s7server.ReturnAccessDenied)
+
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("ok", "DB1.DBW0", common.ValueTypeUint16),
+		newTestRequest("denied", "DB1.DBW2", common.ValueTypeUint16),
+		newTestRequest("missing", "DB9.DBW0", common.ValueTypeUint16),
+		newTestRequest("range", "DB1.DBW300", common.ValueTypeUint16),
+	}
+	values := readValues(t, s, protocols, reqs)
+	if _, ok := values["ok"]; !ok || len(values) != 1 {
+		t.Errorf("HandleReadCommands() = %v, want only 'ok'", values)
+	}
+
+	server.ClearFaults()
+	if values = readValues(t, s, protocols, reqs[:2]); len(values) != 2 {
+		t.Errorf("HandleReadCommands() after ClearFaults = %v, want 2 values", values)
+	}
+}
+
+func TestE2E_Reconnect(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	_ = server.WriteArea(s7server.AreaDB, 1, 0, []byte{0x12, 0x34})
+	reqs := []sdkModel.CommandRequest{newTestRequest("word", "DB1.DBW0", common.ValueTypeUint16)}
+
+	readValues(t, s, protocols, reqs)
+
+	server.DropConnections()
+	if got := readValues(t, s, protocols, reqs)["word"]; got != uint16(0x1234) {
+		t.Errorf("read after dropped connection = %v, want 0x1234", got)
+	}
+
+	server.DropNextRequests(1)
+	if got := readValues(t, s, protocols, reqs)["word"]; got != uint16(0x1234) {
+		t.Errorf("read after dropped request = %v, want 0x1234", got)
+	}
+}
+
+func TestE2E_SlowResponse(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	_ = server.WriteArea(s7server.AreaDB, 1, 0, []byte{0x00, 0x2A})
+	reqs := []sdkModel.CommandRequest{newTestRequest("word", "DB1.DBW0", common.ValueTypeUint16)}
+
+	readValues(t, s, protocols, reqs)
+	server.SetDelay(200 * time.Millisecond)
+	start := time.Now()
+	if got := readValues(t, s, protocols, reqs)["word"]; got != uint16(42) {
+		t.Errorf("slow read = %v, want 42", got)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("slow read took %v, want the injected delay", elapsed)
+	}
+}
+
+func TestE2E_ReadSZL(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+
+	record := make([]byte, 28)
+	binary.BigEndian.PutUint16(record[0:], 1)
+	copy(record[2:], "6ES7 315-2EH14-0AB0 ")
+	server.SetSZL(szlModuleIdentification, 0, len(record), record)
+	// larger than a PDU, the answer is fragmented
+	large := make([]byte, 30*34)
+	for i := range large {
+		large[i] = byte(i)
+	}
+	server.SetSZL(szlComponentIdentification, 0, 34, large)
+
+	reqs := []sdkModel.CommandRequest{
+		{DeviceResourceName: "module", Type: common.ValueTypeObject, Attributes: map[string]any{SZL_ID: szlModuleIdentification}},
+		{DeviceResourceName: "components", Type: common.ValueTypeBinary, Attributes: map[string]any{SZL_ID: szlComponentIdentification}},
+		{DeviceResourceName: "unknown", Type: common.ValueTypeBinary, Attributes: map[string]any{SZL_ID: 0x0F00}},
+	}
+	values := readValues(t, s, protocols, reqs)
+	module, ok := values["module"].(map[string]any)
+	if !ok || fmt.Sprint(module["records"]) == "[]" {
+		t.Errorf("module identification = %v", values["module"])
+	}
+	if got, _ := values["components"].([]byte); string(got) != string(large) {
+		t.Errorf("component identification has %d bytes, want %d", len(got), len(large))
+	}
+	if _, ok := values["unknown"]; ok {
+		t.Errorf("unknown SZL should not be read")
+	}
+}
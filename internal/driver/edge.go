@@ -0,0 +1,89 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+
+	"github.com/spf13/cast"
+)
+
+// Edges of the 'Edge' attribute
+const (
+	edgeRising  = "rising"
+	edgeFalling = "falling"
+	edgeBoth    = "both"
+)
+
+// Tags of the edge readings
+const (
+	edgeTag           = "edge"
+	transitionTimeTag = "transitionTime"
+	pulseDurationTag  = "pulseDuration" // milliseconds the bit was high
+)
+
+// getEdge returns the edge of the resource attributes, or empty for resources without 'Edge'
+func getEdge(attributes map[string]any, valueType string) (string, error) {
+	value, ok := attributes[EDGE]
+	if !ok {
+		return "", nil
+	}
+	edge := strings.ToLower(cast.ToString(value))
+	switch edge {
+	case edgeRising, edgeFalling, edgeBoth:
+	default:
+		return "", fmt.Errorf("%s %v should be rising, falling or both", EDGE, value)
+	}
+	if valueType != common.ValueTypeBool {
+		return "", fmt.Errorf("%s is supported by Bool resources only, not %s", EDGE, valueType)
+	}
+	return edge, nil
+}
+
+// transition records the state of the bit and returns true on a transition of the configured edge.
+// The value is tagged with the edge, the transition time and, on falling edges, the pulse duration.
+func (r *covResource) transition(value *sdkModel.CommandValue, now time.Time) bool {
+	state, err := value.BoolValue()
+	if err != nil {
+		return false
+	}
+	previous := r.state
+	r.state = &state
+	if previous == nil || *previous == state {
+		return false
+	}
+
+	edge := edgeFalling
+	var pulse time.Duration
+	if state {
+		edge = edgeRising
+		r.risingTime = now
+	} else if !r.risingTime.IsZero() {
+		pulse = now.Sub(r.risingTime)
+		r.risingTime = time.Time{}
+	}
+	if r.edge != edgeBoth && r.edge != edge {
+		return false
+	}
+
+	if value.Tags == nil {
+		value.Tags = make(map[string]string)
+	}
+	value.Tags[edgeTag] = edge
+	value.Tags[transitionTimeTag] = now.UTC().Format(time.RFC3339Nano)
+	if pulse > 0 {
+		value.Tags[pulseDurationTag] = strconv.FormatInt(pulse.Milliseconds(), 10)
+	}
+	value.Origin = now.UnixNano()
+	return true
+}
@@ -0,0 +1,106 @@
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestGetEdge(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		valueType  string
+		want       string
+		wantErr    bool
+	}{
+		{name: "no edge", attributes: map[string]any{}, valueType: common.ValueTypeBool},
+		{name: "rising", attributes: map[string]any{EDGE: "Rising"}, valueType: common.ValueTypeBool, want: edgeRising},
+		{name: "both", attributes: map[string]any{EDGE: "both"}, valueType: common.ValueTypeBool, want: edgeBoth},
+		{name: "invalid", attributes: map[string]any{EDGE: "up"}, valueType: common.ValueTypeBool, wantErr: true},
+		{name: "not a bool", attributes: map[string]any{EDGE: "rising"}, valueType: common.ValueTypeInt16, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getEdge(tt.attributes, tt.valueType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getEdge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getEdge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransition(t *testing.T) {
+	start := time.Now()
+	states := []bool{false, true, true, false, true}
+	tests := []struct {
+		edge string
+		want []string // edge tag per state, empty for no event
+	}{
+		{edge: edgeRising, want: []string{"", edgeRising, "", "", edgeRising}},
+		{edge: edgeFalling, want: []string{"", "", "", edgeFalling, ""}},
+		{edge: edgeBoth, want: []string{"", edgeRising, "", edgeFalling, edgeRising}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.edge, func(t *testing.T) {
+			r := &covResource{edge: tt.edge}
+			for i, state := range states {
+				value, _ := sdkModel.NewCommandValue("button", common.ValueTypeBool, state)
+				now := start.Add(time.Duration(i) * 100 * time.Millisecond)
+				got := ""
+				if r.transition(value, now) {
+					got = value.Tags[edgeTag]
+					if value.Origin != now.UnixNano() {
+						t.Errorf("state %d: origin = %d, want the transition time", i, value.Origin)
+					}
+				}
+				if got != tt.want[i] {
+					t.Errorf("state %d: edge = %q, want %q", i, got, tt.want[i])
+				}
+				if got == edgeFalling && value.Tags[pulseDurationTag] != "200" {
+					t.Errorf("state %d: pulse duration = %q, want 200", i, value.Tags[pulseDurationTag])
+				}
+			}
+		})
+	}
+}
+
+func TestPollEdges(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	asyncCh := make(chan *sdkModel.AsyncValues, 16)
+	s.asyncCh = asyncCh
+
+	button, err := newCOVResource(models.DeviceResource{
+		Name:       "button",
+		Attributes: map[string]any{"NodeName": "DB1.DBX4.1", EDGE: edgeRising},
+		Properties: models.ResourceProperties{ValueType: common.ValueTypeBool},
+	})
+	if err != nil {
+		t.Fatalf("newCOVResource() error = %v", err)
+	}
+	resources := []*covResource{button}
+
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, true)
+	_ = server.WriteBit(s7server.AreaDB, 1, 4, 1, true)
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, false)
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, true)
+	_ = server.WriteBit(s7server.AreaDB, 1, 4, 1, false)
+	s.pollCOVResources(context.Background(), testDevice, protocols, resources, false)
+
+	if len(asyncCh) != 1 {
+		t.Fatalf("published %d events, want the rising edge only", len(asyncCh))
+	}
+	acv := <-asyncCh
+	if acv.CommandValues[0].Value != true || acv.CommandValues[0].Tags[edgeTag] != edgeRising {
+		t.Errorf("published %v with tags %v", acv.CommandValues[0].Value, acv.CommandValues[0].Tags)
+	}
+}
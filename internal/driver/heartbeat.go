@@ -0,0 +1,203 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+// Default intervals of the heartbeats
+const (
+	defaultHeartbeatInterval     = 10 * time.Second
+	defaultHostHeartbeatInterval = time.Second
+)
+
+// Status of the PLC heartbeat events
+const (
+	heartbeatStalled   = "stalled"
+	heartbeatRecovered = "recovered"
+)
+
+// HeartbeatConfig is the supervision of the PLC heartbeat and the host heartbeat written to the PLC
+type HeartbeatConfig struct {
+	NodeName     string
+	Interval     time.Duration // the PLC heartbeat changes at least once per interval
+	HostNodeName string
+	HostInterval time.Duration
+}
+
+// getHeartbeatConfig returns the heartbeat configuration from the protocol properties
+func getHeartbeatConfig(pp models.ProtocolProperties) (*HeartbeatConfig, error) {
+	config := &HeartbeatConfig{
+		NodeName:     cast.ToString(pp[HEARTBEAT_NODE_NAME]),
+		Interval:     defaultHeartbeatInterval,
+		HostNodeName: cast.ToString(pp[HOST_HEARTBEAT_NODE_NAME]),
+		HostInterval: defaultHostHeartbeatInterval,
+	}
+	for property, interval := range map[string]*time.Duration{
+		HEARTBEAT_INTERVAL:      &config.Interval,
+		HOST_HEARTBEAT_INTERVAL: &config.HostInterval,
+	} {
+		value, ok := pp[property]
+		if !ok || value == "" {
+			continue
+		}
+		seconds, err := cast.ToIntE(value)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("%s %v is not a positive integer", property, value)
+		}
+		*interval = time.Duration(seconds) * time.Second
+	}
+	return config, nil
+}
+
+// heartbeatMonitor detects a PLC heartbeat which stops changing
+type heartbeatMonitor struct {
+	interval   time.Duration
+	last       any
+	lastChange time.Time
+	stalled    bool
+}
+
+// update records the heartbeat value and returns the status when it stalls or recovers, or empty
+func (m *heartbeatMonitor) update(value any, now time.Time) string {
+	if m.lastChange.IsZero() || !reflect.DeepEqual(value, m.last) {
+		m.last = value
+		m.lastChange = now
+		if m.stalled {
+			m.stalled = false
+			return heartbeatRecovered
+		}
+		return ""
+	}
+	if !m.stalled && now.Sub(m.lastChange) > m.interval {
+		m.stalled = true
+		return heartbeatStalled
+	}
+	return ""
+}
+
+// heartbeatRequest creates the read or write request of a heartbeat address, the value type follows the address size
+func (s *Driver) heartbeatRequest(deviceName string, protocols map[string]models.ProtocolProperties, nodeName string) (sdkModel.CommandRequest, error) {
+	req := sdkModel.CommandRequest{
+		DeviceResourceName: HEARTBEAT_RESOURCE,
+		Attributes:         map[string]any{"NodeName": nodeName},
+	}
+	family, err := getFamily(protocols[Protocol])
+	if err != nil {
+		return req, err
+	}
+	dbInfo, err := s.getFamilyDBInfo(deviceName, family, nodeName)
+	if err != nil {
+		return req, err
+	}
+	switch dbInfo.WordLength {
+	case s7wlbit:
+		req.Type = common.ValueTypeBool
+	case s7wlbyte:
+		req.Type = common.ValueTypeUint8
+	case s7wlword:
+		req.Type = common.ValueTypeUint16
+	default:
+		req.Type = common.ValueTypeUint32
+	}
+	return req, nil
+}
+
+// superviseHeartbeat reads the PLC heartbeat and emits an async event when it stalls and when it recovers
+func (s *Driver) superviseHeartbeat(ctx context.Context, deviceName string, protocols map[string]models.ProtocolProperties, config *HeartbeatConfig) {
+	req, err := s.heartbeatRequest(deviceName, protocols, config.NodeName)
+	if err != nil {
+		s.lc.Errorf("Heartbeat supervision of device %s is not started, error: %v", deviceName, err)
+		return
+	}
+	s.lc.Infof("Heartbeat supervision of device %s started, %s should change every %v", deviceName, config.NodeName, config.Interval)
+
+	// sample twice per interval, so a heartbeat changing once per interval is not missed
+	ticker := time.NewTicker(config.Interval / 2)
+	defer ticker.Stop()
+
+	monitor := &heartbeatMonitor{interval: config.Interval}
+	for {
+		res, err := s.HandleReadCommands(deviceName, protocols, []sdkModel.CommandRequest{req})
+		if err != nil || len(res) == 0 {
+			// a broken connection is not a stalled program
+			s.lc.Debugf("Read heartbeat of device %s failed, error: %v", deviceName, err)
+		} else if status := monitor.update(res[0].Value, time.Now()); status != "" {
+			if status == heartbeatStalled {
+				s.lc.Warnf("PLC program of device %s stalled, heartbeat %s unchanged since %v", deviceName, config.NodeName, monitor.lastChange)
+			} else {
+				s.lc.Infof("PLC program of device %s recovered, heartbeat %s is changing", deviceName, config.NodeName)
+			}
+			s.sendAsyncValue(ctx, deviceName, HEARTBEAT_RESOURCE, common.ValueTypeObject, map[string]any{
+				"status":     status,
+				"nodeName":   config.NodeName,
+				"value":      monitor.last,
+				"lastChange": monitor.lastChange.UTC().Format(time.RFC3339Nano),
+				"interval":   config.Interval.Seconds(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			s.lc.Infof("Heartbeat supervision of device %s stopped", deviceName)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeHostHeartbeat toggles a bit or increments a counter in the PLC, so the PLC program can detect the service is down
+func (s *Driver) writeHostHeartbeat(ctx context.Context, deviceName string, protocols map[string]models.ProtocolProperties, config *HeartbeatConfig) {
+	req, err := s.heartbeatRequest(deviceName, protocols, config.HostNodeName)
+	if err != nil {
+		s.lc.Errorf("Host heartbeat of device %s is not started, error: %v", deviceName, err)
+		return
+	}
+	s.lc.Infof("Host heartbeat of device %s started, %s is written every %v", deviceName, config.HostNodeName, config.HostInterval)
+	ticker := time.NewTicker(config.HostInterval)
+	defer ticker.Stop()
+
+	var count uint32
+	for {
+		count++
+		var value any
+		switch req.Type {
+		case common.ValueTypeBool:
+			value = count%2 == 1
+		case common.ValueTypeUint8:
+			value = uint8(count)
+		case common.ValueTypeUint16:
+			value = uint16(count)
+		default:
+			value = count
+		}
+		param, err := sdkModel.NewCommandValue(req.DeviceResourceName, req.Type, value)
+		if err == nil {
+			err = s.HandleWriteCommands(deviceName, protocols, []sdkModel.CommandRequest{req}, []*sdkModel.CommandValue{param})
+		}
+		if err != nil {
+			s.lc.Debugf("Write host heartbeat of device %s failed, error: %v", deviceName, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.lc.Infof("Host heartbeat of device %s stopped", deviceName)
+			return
+		case <-ticker.C:
+		}
+	}
+}
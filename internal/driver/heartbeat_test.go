@@ -0,0 +1,92 @@
+package driver
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestGetHeartbeatConfig(t *testing.T) {
+	config, err := getHeartbeatConfig(models.ProtocolProperties{HEARTBEAT_NODE_NAME: "DB1.DBW160", HEARTBEAT_INTERVAL: "5"})
+	if err != nil {
+		t.Fatalf("getHeartbeatConfig() error = %v", err)
+	}
+	if config.NodeName != "DB1.DBW160" || config.Interval != 5*time.Second || config.HostNodeName != "" || config.HostInterval != defaultHostHeartbeatInterval {
+		t.Errorf("getHeartbeatConfig() = %+v", config)
+	}
+	for _, pp := range []models.ProtocolProperties{
+		{HEARTBEAT_INTERVAL: "0"},
+		{HOST_HEARTBEAT_INTERVAL: "fast"},
+	} {
+		if _, err = getHeartbeatConfig(pp); err == nil {
+			t.Errorf("getHeartbeatConfig(%v) should fail", pp)
+		}
+	}
+}
+
+func TestHeartbeatMonitor(t *testing.T) {
+	start := time.Now()
+	monitor := &heartbeatMonitor{interval: 10 * time.Second}
+	steps := []struct {
+		value   any
+		elapsed time.Duration
+		want    string
+	}{
+		{value: 1, elapsed: 0},
+		{value: 2, elapsed: 5 * time.Second},
+		{value: 2, elapsed: 15 * time.Second},
+		{value: 2, elapsed: 16 * time.Second, want: heartbeatStalled},
+		{value: 2, elapsed: 30 * time.Second},
+		{value: 3, elapsed: 31 * time.Second, want: heartbeatRecovered},
+		{value: 4, elapsed: 32 * time.Second},
+	}
+	for i, step := range steps {
+		if got := monitor.update(step.value, start.Add(step.elapsed)); got != step.want {
+			t.Errorf("step %d: update() = %q, want %q", i, got, step.want)
+		}
+	}
+}
+
+func TestHeartbeats(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	asyncCh := make(chan *sdkModel.AsyncValues, 16)
+	s.asyncCh = asyncCh
+
+	config := &HeartbeatConfig{
+		NodeName:     "DB1.DBW160",
+		Interval:     200 * time.Millisecond,
+		HostNodeName: "DB1.DBW162",
+		HostInterval: 20 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.superviseHeartbeat(ctx, testDevice, protocols, config)
+	go s.writeHostHeartbeat(ctx, testDevice, protocols, config)
+
+	status := func() string {
+		select {
+		case acv := <-asyncCh:
+			return acv.CommandValues[0].Value.(map[string]any)["status"].(string)
+		case <-time.After(2 * time.Second):
+			return "timeout"
+		}
+	}
+	if got := status(); got != heartbeatStalled {
+		t.Errorf("unchanged heartbeat status = %s, want %s", got, heartbeatStalled)
+	}
+	_ = server.WriteArea(s7server.AreaDB, 1, 160, binary.BigEndian.AppendUint16(nil, 1))
+	if got := status(); got != heartbeatRecovered {
+		t.Errorf("changed heartbeat status = %s, want %s", got, heartbeatRecovered)
+	}
+
+	data, _ := server.ReadArea(s7server.AreaDB, 1, 162, 2)
+	if binary.BigEndian.Uint16(data) == 0 {
+		t.Errorf("host heartbeat is not written")
+	}
+}
@@ -0,0 +1,78 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/robinson/gos7"
+	"github.com/spf13/cast"
+)
+
+// getKeepAliveInterval returns the keepalive interval, zero means disabled
+func getKeepAliveInterval(pp models.ProtocolProperties) (time.Duration, error) {
+	value, ok := pp[KEEPALIVE_INTERVAL]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	interval, err := cast.ToIntE(value)
+	if err != nil || interval < 0 {
+		return 0, fmt.Errorf("%s %v is not a positive integer", KEEPALIVE_INTERVAL, value)
+	}
+	return time.Duration(interval) * time.Second, nil
+}
+
+// keepAliveItem is the byte read by the keepalive requests, an item error like a protected area
+// is still an answer of the PLC and keeps the connection
+func keepAliveItem() []gos7.S7DataItem {
+	return []gos7.S7DataItem{{Area: 0x83, WordLen: s7wlbyte, Start: 0, Amount: 1, Data: make([]byte, 1)}}
+}
+
+// keepAlive reads a byte of a device idle for half the interval, so the connection is never idle for the interval,
+// gos7 doesn't close it after an 'IdleTimeout' longer than the interval and the next request doesn't pay a reconnect.
+// A device down is left to its connection probe.
+func (s *Driver) keepAlive(ctx context.Context, deviceName string, protocols map[string]models.ProtocolProperties, interval time.Duration) {
+	s.lc.Infof("Keepalive of device %s started, interval: %v", deviceName, interval)
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.lc.Infof("Keepalive of device %s stopped", deviceName)
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		c := s.getConnectionState(deviceName)
+		idle, down := time.Since(c.lastActivityTime), c.down
+		s.mu.Unlock()
+		if down || idle < interval/2 {
+			continue
+		}
+
+		s.lc.Debugf("Keepalive of device %s, idle for %v", deviceName, idle)
+		err := readKeepAlive(s.getS7Client(deviceName, protocols))
+		if err != nil {
+			s.lc.Errorf("Keepalive of device %s failed, error: %s, reconnecting...", deviceName, err)
+			err = readKeepAlive(s.reconnect(deviceName, protocols))
+		}
+		// the keepalive counts once, after its reconnect
+		s.updateConnectionState(deviceName, err)
+	}
+}
+
+// readKeepAlive reads the keepalive byte, a client which could not be created is a failure
+func readKeepAlive(s7Client *S7Client) error {
+	if s7Client == nil || s7Client.Client == nil {
+		return fmt.Errorf("S7 client is not created")
+	}
+	return s7Client.Client.AGReadMulti(keepAliveItem(), 1)
+}
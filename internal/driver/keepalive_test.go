@@ -0,0 +1,86 @@
+package driver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+func TestGetKeepAliveInterval(t *testing.T) {
+	for value, want := range map[string]time.Duration{"": 0, "0": 0, "10": 10 * time.Second} {
+		if got, err := getKeepAliveInterval(models.ProtocolProperties{KEEPALIVE_INTERVAL: value}); err != nil || got != want {
+			t.Errorf("getKeepAliveInterval(%q) = %v, %v, want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"-1", "1m"} {
+		if _, err := getKeepAliveInterval(models.ProtocolProperties{KEEPALIVE_INTERVAL: value}); err == nil {
+			t.Errorf("getKeepAliveInterval(%q) should fail", value)
+		}
+	}
+}
+
+func TestE2E_KeepAlive(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	protocols[Protocol][KEEPALIVE_INTERVAL] = "1"
+	reqs := []sdkModel.CommandRequest{newTestRequest("word", "DB1.DBW0", common.ValueTypeUint16)}
+	readValues(t, s, protocols, reqs)
+	s7Client := s.s7Clients[testDevice]
+	if s7Client.Handler.KeepAlive != time.Second {
+		t.Errorf("TCP keepalive = %v, want the keepalive interval", s7Client.Handler.KeepAlive)
+	}
+
+	// gos7 closes the connection idle for 300ms, the keepalive reads every 100ms of idleness
+	s7Client.Handler.IdleTimeout = 300 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	requests := server.Requests()
+	done := make(chan struct{})
+	go func() {
+		s.keepAlive(ctx, testDevice, protocols, 200*time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(time.Second)
+	cancel()
+	<-done
+
+	if n := server.Requests() - requests; n < 3 {
+		t.Errorf("%d keepalive requests in 1s, want at least 3", n)
+	}
+	if s.s7Clients[testDevice] != s7Client {
+		t.Errorf("the keepalive should keep the connection")
+	}
+	requests = server.Requests()
+	readValues(t, s, protocols, reqs)
+	if n := server.Requests() - requests; n != 1 {
+		t.Errorf("the read after the keepalive took %d requests, want 1", n)
+	}
+}
+
+func TestKeepAliveWithoutClient(t *testing.T) {
+	_, s, protocols := newTestServer(t)
+	// the client of an invalid connection type is not created
+	protocols[Protocol][CONNECTION_TYPE] = "XX"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.keepAlive(ctx, testDevice, protocols, 40*time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(500 * time.Millisecond)
+	cancel()
+	<-done
+
+	// each keepalive counts once, the device is DOWN after 'DownAfterFailures' of them and left to its probe
+	s.mu.Lock()
+	c := *s.getConnectionState(testDevice)
+	s.mu.Unlock()
+	if !c.down || c.failures != defaultConfig.downAfterFailures {
+		t.Errorf("connection state after the failed keepalives = %d failures, down %v, want %d failures and down",
+			c.failures, c.down, defaultConfig.downAfterFailures)
+	}
+}
@@ -0,0 +1,193 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/robinson/gos7"
+)
+
+// the item return codes of the S7 protocol, by the error text of gos7
+var s7ReturnCodes = func() map[string]string {
+	codes := make(map[string]string)
+	for _, code := range []uint{0x05, 0x06, 0x07, 0x0A} {
+		codes[gos7.ErrorText(gos7.CPUError(code))] = fmt.Sprintf("0x%02X", code)
+	}
+	return codes
+}()
+
+// s7ReturnCode returns the S7 return code of an item error, the other errors are refused items
+func s7ReturnCode(s7Err string) string {
+	if code, ok := s7ReturnCodes[s7Err]; ok {
+		return code
+	}
+	return "refused"
+}
+
+// deviceMetrics are the metrics of a device, they are registered as '<metric>-<device>' with a 'device' tag,
+// so each metric is reported under its configured name for all the devices
+type deviceMetrics struct {
+	readRequests    gometrics.Counter
+	writeRequests   gometrics.Counter
+	requestErrors   gometrics.Counter
+	reconnects      gometrics.Counter
+	bytesRead       gometrics.Counter
+	bytesWritten    gometrics.Counter
+	roundTrip       gometrics.Timer
+	batchSize       gometrics.Histogram
+	connectionState gometrics.Gauge
+
+	mu         sync.Mutex
+	itemErrors map[string]gometrics.Counter // by S7 return code
+	names      []string                     // registered names
+}
+
+func newDeviceMetrics() *deviceMetrics {
+	return &deviceMetrics{
+		readRequests:    gometrics.NewCounter(),
+		writeRequests:   gometrics.NewCounter(),
+		requestErrors:   gometrics.NewCounter(),
+		reconnects:      gometrics.NewCounter(),
+		bytesRead:       gometrics.NewCounter(),
+		bytesWritten:    gometrics.NewCounter(),
+		roundTrip:       gometrics.NewTimer(),
+		batchSize:       gometrics.NewHistogram(gometrics.NewExpDecaySample(1028, 0.015)),
+		connectionState: gometrics.NewGauge(),
+		itemErrors:      make(map[string]gometrics.Counter),
+	}
+}
+
+// registerMetric registers a metric of the device with the metrics manager of the SDK, if any
+func (s *Driver) registerMetric(deviceName string, m *deviceMetrics, name string, item any, tags map[string]string) {
+	if s.sdk == nil || s.sdk.MetricsManager() == nil {
+		return
+	}
+	fullName := name + "-" + deviceName
+	tags["device"] = deviceName
+	if err := s.sdk.MetricsManager().Register(fullName, item, tags); err != nil {
+		s.lc.Warnf("unable to register metric %s, error: %v", fullName, err)
+		return
+	}
+	m.mu.Lock()
+	m.names = append(m.names, fullName)
+	m.mu.Unlock()
+}
+
+// getDeviceMetrics returns the metrics of a device, they are registered on the first use
+func (s *Driver) getDeviceMetrics(deviceName string) *deviceMetrics {
+	s.mu.Lock()
+	m := s.metrics[deviceName]
+	created := m == nil
+	if created {
+		m = newDeviceMetrics()
+		if s.metrics == nil {
+			s.metrics = make(map[string]*deviceMetrics)
+		}
+		s.metrics[deviceName] = m
+	}
+	s.mu.Unlock()
+
+	if created {
+		for name, item := range map[string]any{
+			METRIC_READ_REQUESTS:    m.readRequests,
+			METRIC_WRITE_REQUESTS:   m.writeRequests,
+			METRIC_REQUEST_ERRORS:   m.requestErrors,
+			METRIC_RECONNECTS:       m.reconnects,
+			METRIC_BYTES_READ:       m.bytesRead,
+			METRIC_BYTES_WRITTEN:    m.bytesWritten,
+			METRIC_ROUND_TRIP:       m.roundTrip,
+			METRIC_BATCH_SIZE:       m.batchSize,
+			METRIC_CONNECTION_STATE: m.connectionState,
+		} {
+			s.registerMetric(deviceName, m, name, item, map[string]string{})
+		}
+	}
+	return m
+}
+
+// unregisterMetrics drops the metrics of a removed device
+func (s *Driver) unregisterMetrics(deviceName string) {
+	s.mu.Lock()
+	m := s.metrics[deviceName]
+	delete(s.metrics, deviceName)
+	s.mu.Unlock()
+	if m == nil || s.sdk == nil || s.sdk.MetricsManager() == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, name := range m.names {
+		s.sdk.MetricsManager().Unregister(name)
+	}
+}
+
+// countItemError counts an item error of a device by its S7 return code
+func (s *Driver) countItemError(deviceName string, m *deviceMetrics, s7Err string) {
+	code := s7ReturnCode(s7Err)
+	m.mu.Lock()
+	counter := m.itemErrors[code]
+	created := counter == nil
+	if created {
+		counter = gometrics.NewCounter()
+		m.itemErrors[code] = counter
+	}
+	m.mu.Unlock()
+	if created {
+		s.registerMetric(deviceName, m, METRIC_ITEM_ERRORS+"-"+code, counter, map[string]string{"code": code})
+	}
+	counter.Inc(1)
+}
+
+// itemBytes returns the data bytes of an item, a bit takes a byte
+func itemBytes(item gos7.S7DataItem) int64 {
+	return int64(item.Amount * max(valueSize(item.WordLen, ""), 1))
+}
+
+// recordRequest records a multi-read or multi-write request of a device started at start:
+// its batch size, its round trip, and the data bytes or the errors of its items
+func (s *Driver) recordRequest(deviceName string, write bool, items []gos7.S7DataItem, start time.Time, err error) {
+	m := s.getDeviceMetrics(deviceName)
+	if write {
+		m.writeRequests.Inc(1)
+	} else {
+		m.readRequests.Inc(1)
+	}
+	m.batchSize.Update(int64(len(items)))
+	if err != nil {
+		m.requestErrors.Inc(1)
+		return
+	}
+	m.roundTrip.UpdateSince(start)
+
+	var bytes int64
+	for _, item := range items {
+		if item.Error != "" {
+			s.countItemError(deviceName, m, item.Error)
+			continue
+		}
+		bytes += itemBytes(item)
+	}
+	if write {
+		m.bytesWritten.Inc(bytes)
+	} else {
+		m.bytesRead.Inc(bytes)
+	}
+}
+
+// recordConnection records the connection state of a device, 1 when connected
+func (s *Driver) recordConnection(deviceName string, connected bool) {
+	m := s.getDeviceMetrics(deviceName)
+	if connected {
+		m.connectionState.Update(1)
+	} else {
+		m.connectionState.Update(0)
+	}
+}
@@ -0,0 +1,59 @@
+package driver
+
+import (
+	"testing"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/robinson/gos7"
+)
+
+func TestS7ReturnCode(t *testing.T) {
+	for code, want := range map[uint]string{0x05: "0x05", 0x0A: "0x0A", 0x03: "refused"} {
+		if got := s7ReturnCode(gos7.ErrorText(gos7.CPUError(code))); got != want {
+			t.Errorf("s7ReturnCode(0x%02X) = %s, want %s", code, got, want)
+		}
+	}
+}
+
+func TestE2E_Metrics(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("word", "DB1.DBW0", common.ValueTypeUint16),
+		newTestRequest("level", "DB1.DBD100", common.ValueTypeFloat32),
+		newTestRequest("missing", "DB9.DBW0", common.ValueTypeUint16),
+		newTestRequest("range", "DB1.DBW300", common.ValueTypeUint16),
+	}
+	readValues(t, s, protocols, reqs)
+
+	m := s.getDeviceMetrics(testDevice)
+	if m.readRequests.Count() != 1 || m.bytesRead.Count() != 6 || m.batchSize.Max() != 4 || m.roundTrip.Count() != 1 {
+		t.Errorf("read metrics = %d requests, %d bytes, %d items, %d round trips",
+			m.readRequests.Count(), m.bytesRead.Count(), m.batchSize.Max(), m.roundTrip.Count())
+	}
+	if m.itemErrors["0x0A"].Count() != 1 || m.itemErrors["0x05"].Count() != 1 {
+		t.Errorf("item errors = %v", m.itemErrors)
+	}
+	if m.connectionState.Value() != 1 {
+		t.Errorf("connection state = %d, want 1", m.connectionState.Value())
+	}
+
+	cv, _ := sdkModel.NewCommandValue("word", common.ValueTypeUint16, uint16(7))
+	if err := s.HandleWriteCommands(testDevice, protocols, reqs[:1], []*sdkModel.CommandValue{cv}); err != nil {
+		t.Fatalf("HandleWriteCommands() error = %v", err)
+	}
+	if m.writeRequests.Count() != 1 || m.bytesWritten.Count() != 2 {
+		t.Errorf("write metrics = %d requests, %d bytes", m.writeRequests.Count(), m.bytesWritten.Count())
+	}
+
+	server.DropNextRequests(1)
+	readValues(t, s, protocols, reqs[:1])
+	if m.requestErrors.Count() != 1 || m.reconnects.Count() != 1 || m.connectionState.Value() != 1 {
+		t.Errorf("reconnect metrics = %d errors, %d reconnects, state %d", m.requestErrors.Count(), m.reconnects.Count(), m.connectionState.Value())
+	}
+
+	s.unregisterMetrics(testDevice)
+	if _, ok := s.metrics[testDevice]; ok {
+		t.Errorf("unregisterMetrics() should drop the metrics of the device")
+	}
+}
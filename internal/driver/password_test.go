@@ -0,0 +1,130 @@
+package driver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/v4/pkg/interfaces"
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	bootstrapInterfaces "github.com/edgexfoundry/go-mod-bootstrap/v4/bootstrap/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+// testSecretProvider returns the secrets of a map, a missing secret or key fails
+type testSecretProvider struct {
+	bootstrapInterfaces.SecretProvider
+	secrets map[string]map[string]string
+}
+
+func (p *testSecretProvider) GetSecret(secretName string, keys ...string) (map[string]string, error) {
+	secret, ok := p.secrets[secretName]
+	if !ok {
+		return nil, fmt.Errorf("secret %s is not found", secretName)
+	}
+	values := make(map[string]string)
+	for _, key := range keys {
+		value, ok := secret[key]
+		if !ok {
+			return nil, fmt.Errorf("key %s of secret %s is not found", key, secretName)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// testSDK is the SDK of the tests, it provides the secrets and the profile of every device
+type testSDK struct {
+	interfaces.DeviceServiceSDK
+	secretProvider *testSecretProvider
+	profile        models.DeviceProfile
+}
+
+func (sdk *testSDK) GetDeviceByName(name string) (models.Device, error) {
+	return models.Device{Name: name, ProfileName: sdk.profile.Name}, nil
+}
+
+func (sdk *testSDK) GetProfileByName(name string) (models.DeviceProfile, error) {
+	if name != sdk.profile.Name {
+		return models.DeviceProfile{}, fmt.Errorf("profile %s is not found", name)
+	}
+	return sdk.profile, nil
+}
+
+func (sdk *testSDK) SecretProvider() bootstrapInterfaces.SecretProvider {
+	return sdk.secretProvider
+}
+
+func (sdk *testSDK) MetricsManager() bootstrapInterfaces.MetricsManager {
+	return nil
+}
+
+func newTestSDK(secrets map[string]map[string]string) *testSDK {
+	return &testSDK{secretProvider: &testSecretProvider{secrets: secrets}}
+}
+
+func TestGetSessionPassword(t *testing.T) {
+	s := &Driver{
+		lc: logger.NewClient("S7", "Error"),
+		sdk: newTestSDK(map[string]map[string]string{
+			"empty":    {SECRET_PASSWORD_KEY: ""},
+			"long":     {SECRET_PASSWORD_KEY: "123456789"},
+			"valid":    {SECRET_PASSWORD_KEY: "s7pass"},
+			"longest":  {SECRET_PASSWORD_KEY: "12345678"},
+			"nopasswd": {"username": "admin"},
+		}),
+	}
+	tests := []struct {
+		name     string
+		secret   string
+		password string
+		wantErr  bool
+	}{
+		{name: "missing secret", secret: "missing", wantErr: true},
+		{name: "missing password key", secret: "nopasswd", wantErr: true},
+		{name: "empty password", secret: "empty", wantErr: true},
+		{name: "more than 8 characters", secret: "long", wantErr: true},
+		{name: "valid", secret: "valid", password: "s7pass"},
+		{name: "8 characters", secret: "longest", password: "12345678"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password, err := s.getSessionPassword(tt.secret)
+			if (err != nil) != tt.wantErr || password != tt.password {
+				t.Errorf("getSessionPassword() = %q, %v, want %q, wantErr %v", password, err, tt.password, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := (&Driver{}).getSessionPassword("valid"); err == nil {
+		t.Errorf("getSessionPassword() without the SDK should fail")
+	}
+}
+
+func TestE2E_SessionPasswordAfterReconnect(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	server.SetPassword("s7pass")
+	s.sdk = newTestSDK(map[string]map[string]string{"plc": {SECRET_PASSWORD_KEY: "s7pass"}})
+	reqs := []sdkModel.CommandRequest{newTestRequest("int", "DB1.DBW0", common.ValueTypeInt16)}
+
+	// a client without the password is refused
+	if err := s.getS7Client(testDevice, protocols).Client.AGReadDB(1, 0, 2, make([]byte, 2)); err == nil {
+		t.Fatalf("read without the session password should fail")
+	}
+
+	protocols[Protocol][PASSWORD] = "plc"
+	s.mu.Lock()
+	s.s7Clients[testDevice] = nil
+	s.mu.Unlock()
+	if _, err := s.HandleReadCommands(testDevice, protocols, reqs); err != nil {
+		t.Fatalf("HandleReadCommands() with the session password error = %v", err)
+	}
+
+	// the password is set again on the new connection
+	server.DropConnections()
+	res, err := s.HandleReadCommands(testDevice, protocols, reqs)
+	if err != nil || len(res) != 1 {
+		t.Fatalf("HandleReadCommands() after the reconnect = %v, %v, want the value", res, err)
+	}
+}
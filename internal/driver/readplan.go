@@ -0,0 +1,350 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/robinson/gos7"
+	"github.com/spf13/cast"
+)
+
+const (
+	// maxReadItems is the number of items of an AGReadMulti request
+	maxReadItems = 20
+	// defaultPDULength is the PDU length of the S7-300 CPUs, the smallest one, used until the PDU length is negotiated
+	defaultPDULength = 240
+	// maxRangeGap is the number of unused bytes read to join two ranges, an item costs 12 request and 4 reply bytes
+	maxRangeGap = 8
+	// headers of the AGReadMulti requests and replies, and of their items
+	readRequestHeader   = 19
+	readRequestItem     = 12
+	readReplyHeader     = 18
+	readReplyItemHeader = 4
+)
+
+// readItem is a resource of a read plan, with its parsed address and attributes
+type readItem struct {
+	nodeName   string
+	valueType  string
+	attributes string // the attributes which the item is parsed from
+	err        error  // invalid NodeName or attributes, the item is not read
+	wordLength int
+	order      string
+	sc         *scaling
+	encoding   string
+
+	area     int
+	dbNumber int
+	start    int // first byte in the area
+	bit      int
+	size     int // bytes of the value, 0 for bits
+	rng      *readRange
+	offset   int    // first byte in the range
+	buffer   []byte // the 8 bytes of the value, decoded from the range
+	s7Err    string // error of the last read
+}
+
+// extent returns the bytes of the item in its area
+func (item *readItem) extent() int {
+	return max(item.size, 1)
+}
+
+// readRange is the consecutive bytes of an area read by one item of AGReadMulti
+type readRange struct {
+	area     int
+	dbNumber int
+	start    int
+	size     int
+	items    []*readItem
+	data     []byte
+}
+
+// readPlan is a command of a device compiled once: the parsed addresses, the items joined in ranges
+// and the ranges in batches fitting the PDU, with their buffers
+type readPlan struct {
+	mu        sync.Mutex
+	pduLength int
+	batchSize int
+	items     []*readItem
+	ranges    []*readRange
+	s7Items   []gos7.S7DataItem // the items of the ranges
+	batches   [][]gos7.S7DataItem
+}
+
+// commandKey identifies the command of the requests by their resources
+func commandKey(reqs []sdkModel.CommandRequest) string {
+	var key strings.Builder
+	for i, req := range reqs {
+		if i > 0 {
+			key.WriteByte(',')
+		}
+		key.WriteString(req.DeviceResourceName)
+	}
+	return key.String()
+}
+
+// matches returns true when the plan was compiled from the same attributes and value types, and for the PDU length
+func (p *readPlan) matches(reqs []sdkModel.CommandRequest, pduLength int) bool {
+	if p.pduLength != pduLength || len(p.items) != len(reqs) {
+		return false
+	}
+	for i, req := range reqs {
+		if p.items[i].valueType != req.Type || p.items[i].attributes != fmt.Sprint(req.Attributes) {
+			return false
+		}
+	}
+	return true
+}
+
+// newReadItem parses the NodeName and the attributes of a request
+func newReadItem(req sdkModel.CommandRequest, family string, table symbolTable) *readItem {
+	item := &readItem{
+		nodeName:   cast.ToString(req.Attributes["NodeName"]),
+		valueType:  req.Type,
+		attributes: fmt.Sprint(req.Attributes),
+		buffer:     make([]byte, 8), // 8 bytes, the 64-bit values
+	}
+	address, err := translateVAddress(family, table.resolve(item.nodeName))
+	var dbInfo *DBInfo
+	if err == nil {
+		dbInfo, err = ParseAddress(address)
+	}
+	if err == nil {
+		item.order, err = getByteOrder(req.Attributes)
+	}
+	if err == nil {
+		item.sc, err = getScaling(req.Attributes, req.Type)
+	}
+	if err == nil {
+		item.encoding, err = getEncoding(req.Attributes, req.Type)
+	}
+	if err != nil {
+		item.err = err
+		return item
+	}
+
+	item.wordLength = dbInfo.WordLength
+	item.area = dbInfo.Area
+	item.dbNumber = dbInfo.DBNumber
+	item.start = dbInfo.Start
+	item.size = valueSize(dbInfo.WordLength, rawValueType(req.Type, item.sc != nil, item.encoding))
+	if dbInfo.WordLength == s7wlbit {
+		item.start = dbInfo.Start >> 3
+		item.bit = dbInfo.Start & 0x07
+	}
+	return item
+}
+
+// compileReadPlan parses the requests, joins the items of an area closer than maxRangeGap in ranges
+// and puts the ranges in batches of at most batchSize items fitting the PDU length
+func compileReadPlan(reqs []sdkModel.CommandRequest, family string, table symbolTable, pduLength int, batchSize int) *readPlan {
+	plan := &readPlan{pduLength: pduLength, batchSize: batchSize}
+	var valid []*readItem
+	for _, req := range reqs {
+		item := newReadItem(req, family, table)
+		plan.items = append(plan.items, item)
+		if item.err == nil {
+			valid = append(valid, item)
+		}
+	}
+	sort.SliceStable(valid, func(i, j int) bool {
+		a, b := valid[i], valid[j]
+		if a.area != b.area {
+			return a.area < b.area
+		}
+		if a.dbNumber != b.dbNumber {
+			return a.dbNumber < b.dbNumber
+		}
+		return a.start < b.start
+	})
+
+	// a range is read by a single item of the reply
+	maxRangeSize := (pduLength - readReplyHeader - readReplyItemHeader) &^ 1
+	var current *readRange
+	for _, item := range valid {
+		end := item.start + item.extent()
+		if current != nil && item.area == current.area && item.dbNumber == current.dbNumber &&
+			item.start <= current.start+current.size+maxRangeGap && end-current.start <= maxRangeSize {
+			current.size = max(current.size, end-current.start)
+		} else {
+			current = &readRange{area: item.area, dbNumber: item.dbNumber, start: item.start, size: item.extent()}
+			plan.ranges = append(plan.ranges, current)
+		}
+		current.items = append(current.items, item)
+		item.rng = current
+	}
+
+	plan.s7Items = make([]gos7.S7DataItem, len(plan.ranges))
+	for i, r := range plan.ranges {
+		r.data = make([]byte, r.size)
+		for _, item := range r.items {
+			item.offset = item.start - r.start
+		}
+		plan.s7Items[i] = gos7.S7DataItem{
+			Area:     r.area,
+			WordLen:  s7wlbyte,
+			DBNumber: r.dbNumber,
+			Start:    r.start,
+			Amount:   r.size,
+			Data:     r.data,
+		}
+	}
+	plan.batches = batchItems(plan.s7Items, pduLength, batchSize)
+	return plan
+}
+
+// batchItems splits the items in batches of AGReadMulti requests of at most batchSize items,
+// whose requests and replies fit the PDU length
+func batchItems(items []gos7.S7DataItem, pduLength int, batchSize int) [][]gos7.S7DataItem {
+	batchSize = min(max(batchSize, 1), maxReadItems)
+	var batches [][]gos7.S7DataItem
+	first, request, reply := 0, readRequestHeader, readReplyHeader
+	for i, item := range items {
+		// the data of the items is padded to even sizes
+		size := readReplyItemHeader + item.Amount + item.Amount%2
+		if i > first && (i-first == batchSize || request+readRequestItem > pduLength || reply+size > pduLength) {
+			batches = append(batches, items[first:i])
+			first, request, reply = i, readRequestHeader, readReplyHeader
+		}
+		request += readRequestItem
+		reply += size
+	}
+	if first < len(items) {
+		batches = append(batches, items[first:])
+	}
+	return batches
+}
+
+// value copies the bytes of the item from its range to its buffer, the bit of bit items
+func (item *readItem) value() []byte {
+	clear(item.buffer)
+	data := item.rng.data[item.offset:]
+	if item.wordLength == s7wlbit {
+		item.buffer[0] = (data[0] >> item.bit) & 0x01
+	} else {
+		copy(item.buffer, data[:item.size])
+	}
+	return item.buffer
+}
+
+// getPDULength returns the negotiated PDU length of the connection, or the default one
+func getPDULength(s7Client *S7Client) int {
+	if s7Client != nil && s7Client.Handler != nil && s7Client.Handler.PDULength > 0 {
+		return s7Client.Handler.PDULength
+	}
+	return defaultPDULength
+}
+
+// getReadPlan returns the cached read plan of the command, it is compiled on the first read of the command
+// and again when the attributes, the value types or the PDU length changed
+func (s *Driver) getReadPlan(deviceName string, family string, pduLength int, reqs []sdkModel.CommandRequest) *readPlan {
+	key := commandKey(reqs)
+	s.mu.Lock()
+	plan := s.readPlans[deviceName][key]
+	table := s.symbolTables[deviceName]
+	s.mu.Unlock()
+	if plan != nil && plan.matches(reqs, pduLength) {
+		return plan
+	}
+
+	plan = compileReadPlan(reqs, family, table, pduLength, s.getConfig().batchSize)
+	s.lc.Debugf("Read plan of device %s compiled: %d resources, %d ranges, %d batches", deviceName, len(plan.items), len(plan.ranges), len(plan.batches))
+	s.mu.Lock()
+	if s.readPlans == nil {
+		s.readPlans = make(map[string]map[string]*readPlan)
+	}
+	if s.readPlans[deviceName] == nil {
+		s.readPlans[deviceName] = make(map[string]*readPlan)
+	}
+	s.readPlans[deviceName][key] = plan
+	s.mu.Unlock()
+	return plan
+}
+
+// invalidateReadPlans drops the read plans of a device, they are compiled again by the next reads
+func (s *Driver) invalidateReadPlans(deviceName string) {
+	s.mu.Lock()
+	delete(s.readPlans, deviceName)
+	s.mu.Unlock()
+}
+
+// readBatch reads a batch with AGReadMulti, if error, reconnects and tries 'RetryCount' times,
+// the items of a failed batch get its error. The connection state records the result once the retries are used up.
+func (s *Driver) readBatch(deviceName string, protocols map[string]models.ProtocolProperties, batch []gos7.S7DataItem) error {
+	for i := range batch {
+		batch[i].Error = ""
+	}
+	s7Client := s.getS7Client(deviceName, protocols)
+	var err error
+	for retrytimes := s.getConfig().retryCount; retrytimes > 0; retrytimes-- {
+		start := time.Now()
+		err = s7Client.Client.AGReadMulti(batch, len(batch))
+		s.recordRequest(deviceName, false, batch, start, err)
+		if err == nil {
+			s.updateConnectionState(deviceName, nil)
+			return nil
+		}
+		s.lc.Errorf("AGReadMulti Error: %s, reconnecting...", err)
+		s7Client = s.reconnect(deviceName, protocols)
+	}
+	s.updateConnectionState(deviceName, err)
+	for i := range batch {
+		batch[i].Error = err.Error()
+	}
+	return err
+}
+
+// read reads the ranges of the plan and records the error of each item. The ranges of several items
+// failing by an item error, like an address out of the DB, are read again item by item, so only
+// the failing items fail.
+func (s *Driver) read(deviceName string, protocols map[string]models.ProtocolProperties, plan *readPlan) {
+	// the PLC answered the batch of the range, its error is an item error
+	answered := make([]bool, 0, len(plan.ranges))
+	for _, batch := range plan.batches {
+		err := s.readBatch(deviceName, protocols, batch)
+		for range batch {
+			answered = append(answered, err == nil)
+		}
+	}
+
+	var alone []gos7.S7DataItem
+	var aloneItems []*readItem
+	for i, r := range plan.ranges {
+		s7Err := plan.s7Items[i].Error
+		for _, item := range r.items {
+			item.s7Err = s7Err
+		}
+		if s7Err == "" || !answered[i] || len(r.items) < 2 {
+			continue
+		}
+		for _, item := range r.items {
+			alone = append(alone, gos7.S7DataItem{
+				Area:     r.area,
+				WordLen:  s7wlbyte,
+				DBNumber: r.dbNumber,
+				Start:    item.start,
+				Amount:   item.extent(),
+				Data:     r.data[item.offset : item.offset+item.extent()],
+			})
+			aloneItems = append(aloneItems, item)
+		}
+	}
+	for _, batch := range batchItems(alone, plan.pduLength, plan.batchSize) {
+		_ = s.readBatch(deviceName, protocols, batch)
+	}
+	for i, item := range aloneItems {
+		item.s7Err = alone[i].Error
+	}
+}
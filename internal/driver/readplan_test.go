@@ -0,0 +1,127 @@
+package driver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+)
+
+func TestCompileReadPlan(t *testing.T) {
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("level", "DB1.DBD4", common.ValueTypeFloat32),
+		newTestRequest("status", "DB1.DBW0", common.ValueTypeUint16),
+		newTestRequest("ready", "DB1.DBX2.3", common.ValueTypeBool),
+		newTestRequest("far", "DB1.DBW100", common.ValueTypeInt16),
+		newTestRequest("total", "DB2.DBD0", common.ValueTypeFloat64),
+		newTestRequest("input", "I0.1", common.ValueTypeBool),
+		newTestRequest("typo", "DB1.DBW2x", common.ValueTypeInt16),
+	}
+	plan := compileReadPlan(reqs, familyS7, nil, defaultPDULength, maxReadItems)
+	var ranges []string
+	for _, r := range plan.ranges {
+		ranges = append(ranges, fmt.Sprintf("%#x/%d/%d+%d:%d", r.area, r.dbNumber, r.start, r.size, len(r.items)))
+	}
+	want := "[0x81/0/0+1:1 0x84/1/0+8:3 0x84/1/100+2:1 0x84/2/0+8:1]"
+	if fmt.Sprint(ranges) != want {
+		t.Errorf("ranges = %v, want %s", ranges, want)
+	}
+	if len(plan.batches) != 1 || len(plan.batches[0]) != 4 {
+		t.Errorf("batches = %d, want a single batch of 4 items", len(plan.batches))
+	}
+	if plan.items[6].err == nil || plan.items[6].rng != nil {
+		t.Errorf("invalid NodeName %s should not be read", plan.items[6].nodeName)
+	}
+	if ready := plan.items[2]; ready.offset != 2 || ready.bit != 3 {
+		t.Errorf("bit item = %+v", ready)
+	}
+
+	if !plan.matches(reqs, defaultPDULength) || plan.matches(reqs, 480) || plan.matches(reqs[1:], defaultPDULength) {
+		t.Errorf("matches() should follow the requests and the PDU length")
+	}
+	changed := append([]sdkModel.CommandRequest{newTestRequest("level", "DB1.DBD8", common.ValueTypeFloat32)}, reqs[1:]...)
+	if plan.matches(changed, defaultPDULength) {
+		t.Errorf("matches() of a changed NodeName should fail")
+	}
+}
+
+func TestBatchItems(t *testing.T) {
+	var reqs []sdkModel.CommandRequest
+	for i := 0; i < 30; i++ {
+		reqs = append(reqs, newTestRequest(fmt.Sprint(i), fmt.Sprintf("DB%d.DBD0", i+1), common.ValueTypeUint32))
+	}
+	// 19 + 12 * 18 bytes of request fit 240 bytes
+	plan := compileReadPlan(reqs, familyS7, nil, defaultPDULength, maxReadItems)
+	if len(plan.batches) != 2 || len(plan.batches[0]) != 18 {
+		t.Errorf("batches of a 240 bytes PDU = %d, first %d items", len(plan.batches), len(plan.batches[0]))
+	}
+	plan = compileReadPlan(reqs, familyS7, nil, 960, maxReadItems)
+	if len(plan.batches) != 2 || len(plan.batches[0]) != maxReadItems {
+		t.Errorf("batches of a 960 bytes PDU = %d, first %d items", len(plan.batches), len(plan.batches[0]))
+	}
+	plan = compileReadPlan(reqs, familyS7, nil, 960, 8)
+	if len(plan.batches) != 4 || len(plan.batches[0]) != 8 {
+		t.Errorf("batches of 8 items = %d, first %d items", len(plan.batches), len(plan.batches[0]))
+	}
+
+	// the ranges are limited to the reply of a single item
+	reqs = reqs[:0]
+	for offset := 0; offset < 400; offset += 4 {
+		reqs = append(reqs, newTestRequest(fmt.Sprint(offset), fmt.Sprintf("DB1.DBD%d", offset), common.ValueTypeUint32))
+	}
+	plan = compileReadPlan(reqs, familyS7, nil, defaultPDULength, maxReadItems)
+	if len(plan.ranges) != 2 || plan.ranges[0].size != 216 || len(plan.batches) != 2 {
+		t.Errorf("ranges of 400 bytes = %d, first %d bytes, %d batches", len(plan.ranges), plan.ranges[0].size, len(plan.batches))
+	}
+}
+
+func TestE2E_ReadPlan(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	db := make([]byte, 16)
+	binary.BigEndian.PutUint16(db[0:], 0x0102)
+	db[2] = 0x08 // DBX2.3
+	binary.BigEndian.PutUint32(db[4:], math.Float32bits(1.5))
+	server.SetDB(1, db)
+
+	reqs := []sdkModel.CommandRequest{
+		newTestRequest("level", "DB1.DBD4", common.ValueTypeFloat32),
+		newTestRequest("status", "DB1.DBW0", common.ValueTypeUint16),
+		newTestRequest("ready", "DB1.DBX2.3", common.ValueTypeBool),
+		newTestRequest("low", "DB1.DBB1", common.ValueTypeUint8),
+	}
+	readValues(t, s, protocols, reqs)
+	requests := server.Requests()
+	values := readValues(t, s, protocols, reqs)
+	if values["level"] != float32(1.5) || values["status"] != uint16(0x0102) || values["ready"] != true || values["low"] != uint8(2) {
+		t.Errorf("read values = %v", values)
+	}
+	if n := server.Requests() - requests; n != 1 {
+		t.Errorf("the coalesced command took %d requests, want 1", n)
+	}
+	plan := s.readPlans[testDevice][commandKey(reqs)]
+	if plan == nil || len(plan.ranges) != 1 {
+		t.Fatalf("read plan = %+v, want a single range", plan)
+	}
+
+	// a device update drops the plans, a changed NodeName compiles the plan again
+	s.invalidateReadPlans(testDevice)
+	if _, ok := s.readPlans[testDevice]; ok {
+		t.Errorf("invalidateReadPlans() should drop the plans of the device")
+	}
+	reqs[0] = newTestRequest("level", "DB1.DBW8", common.ValueTypeInt16)
+	if values = readValues(t, s, protocols, reqs); values["level"] != int16(0) {
+		t.Errorf("read values = %v", values)
+	}
+	if s.readPlans[testDevice][commandKey(reqs)].items[0].nodeName != "DB1.DBW8" {
+		t.Errorf("read plan should be compiled again")
+	}
+
+	// a changed attribute of the same NodeName compiles the plan again
+	reqs[1].Attributes = map[string]any{"NodeName": "DB1.DBW0", BYTE_ORDER: "DCBA"}
+	if values = readValues(t, s, protocols, reqs); values["status"] != uint16(0x0201) {
+		t.Errorf("read value with the changed byte order = %v, want 0x0201", values["status"])
+	}
+}
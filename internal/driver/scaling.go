@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+
+	"github.com/spf13/cast"
+)
+
+// Siemens analog values, 0 to 27648 is the nominal range of the analog modules
+const (
+	defaultRawMin = 0
+	defaultRawMax = 27648
+	rawOverflow   = math.MaxInt16 // 32767, above the measuring range
+	rawWireBreak  = math.MinInt16 // -32768, wire break of live-zero inputs, or below the measuring range
+)
+
+// Quality of the scaled readings
+const (
+	qualityTag        = "quality"
+	qualityGood       = "good"
+	qualityOverrange  = "overrange"  // above the raw range, clamped to it
+	qualityUnderrange = "underrange" // below the raw range, clamped to it
+	qualityOverflow   = "overflow"
+	qualityWireBreak  = "wireBreak"
+)
+
+// scaling is the linear scaling of raw counts to engineering units
+type scaling struct {
+	rawMin, rawMax float64
+	euMin, euMax   float64
+}
+
+// getScaling returns the scaling of the resource attributes, or nil for resources without
+// 'RawMin', 'RawMax', 'EUMin' and 'EUMax'. The raw range defaults to the Siemens 0 to 27648
+// and the engineering range to 0 to 100.
+func getScaling(attributes map[string]any, valueType string) (*scaling, error) {
+	sc := &scaling{rawMin: defaultRawMin, rawMax: defaultRawMax, euMin: 0, euMax: 100}
+	var found bool
+	for attribute, limit := range map[string]*float64{
+		RAW_MIN: &sc.rawMin,
+		RAW_MAX: &sc.rawMax,
+		EU_MIN:  &sc.euMin,
+		EU_MAX:  &sc.euMax,
+	} {
+		value, ok := attributes[attribute]
+		if !ok {
+			continue
+		}
+		found = true
+		f, err := cast.ToFloat64E(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s %v is not a number", attribute, value)
+		}
+		*limit = f
+	}
+	if !found {
+		return nil, nil
+	}
+	if valueType != common.ValueTypeFloat32 && valueType != common.ValueTypeFloat64 {
+		return nil, fmt.Errorf("scaled resources should be Float32 or Float64, not %s", valueType)
+	}
+	if sc.rawMin == sc.rawMax || sc.euMin == sc.euMax {
+		return nil, fmt.Errorf("%s and %s, %s and %s should differ", RAW_MIN, RAW_MAX, EU_MIN, EU_MAX)
+	}
+	return sc, nil
+}
+
+// scaledRawType returns the value type of the raw counts, following the address size
+func scaledRawType(wordLength int) string {
+	switch wordLength {
+	case s7wlbyte:
+		return common.ValueTypeUint8
+	case s7wlword, s7wlint:
+		return common.ValueTypeInt16
+	default:
+		return common.ValueTypeInt32
+	}
+}
+
+// read scales the raw counts of the buffer, clamped to the engineering range, and returns their quality
+func (sc *scaling) read(buffer []byte, wordLength int) (float64, string, error) {
+	rawType := scaledRawType(wordLength)
+	value, err := getCommandValueType(buffer, rawType)
+	if err != nil {
+		return 0, "", err
+	}
+	raw := cast.ToFloat64(value)
+	low, high := math.Min(sc.rawMin, sc.rawMax), math.Max(sc.rawMin, sc.rawMax)
+
+	quality := qualityGood
+	switch {
+	case rawType == common.ValueTypeInt16 && raw == rawOverflow:
+		quality = qualityOverflow
+	case rawType == common.ValueTypeInt16 && raw == rawWireBreak:
+		quality = qualityWireBreak
+	case raw > high:
+		quality = qualityOverrange
+	case raw < low:
+		quality = qualityUnderrange
+	}
+	raw = math.Max(low, math.Min(high, raw))
+	return sc.euMin + (raw-sc.rawMin)*(sc.euMax-sc.euMin)/(sc.rawMax-sc.rawMin), quality, nil
+}
+
+// write converts the engineering value to the raw counts, clamped to the raw range
+func (sc *scaling) write(value float64, wordLength int) any {
+	raw := sc.rawMin + (value-sc.euMin)*(sc.rawMax-sc.rawMin)/(sc.euMax-sc.euMin)
+	raw = math.Round(math.Max(math.Min(sc.rawMin, sc.rawMax), math.Min(math.Max(sc.rawMin, sc.rawMax), raw)))
+	switch scaledRawType(wordLength) {
+	case common.ValueTypeUint8:
+		return uint8(raw)
+	case common.ValueTypeInt16:
+		return int16(raw)
+	default:
+		return int32(raw)
+	}
+}
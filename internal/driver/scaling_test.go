@@ -0,0 +1,130 @@
+package driver
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+
+	"github.com/edgexfoundry/device-s7/internal/s7server"
+)
+
+func TestGetScaling(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		valueType  string
+		want       *scaling
+		wantErr    bool
+	}{
+		{name: "no scaling", attributes: map[string]any{}, valueType: common.ValueTypeFloat32},
+		{name: "defaults", attributes: map[string]any{EU_MAX: 10}, valueType: common.ValueTypeFloat32, want: &scaling{rawMax: 27648, euMax: 10}},
+		{name: "ranges", attributes: map[string]any{RAW_MIN: "5530", RAW_MAX: 27648, EU_MIN: -50, EU_MAX: 150.5}, valueType: common.ValueTypeFloat64,
+			want: &scaling{rawMin: 5530, rawMax: 27648, euMin: -50, euMax: 150.5}},
+		{name: "not a number", attributes: map[string]any{EU_MAX: "high"}, valueType: common.ValueTypeFloat32, wantErr: true},
+		{name: "empty range", attributes: map[string]any{EU_MIN: 10, EU_MAX: 10}, valueType: common.ValueTypeFloat32, wantErr: true},
+		{name: "not a float", attributes: map[string]any{EU_MAX: 10}, valueType: common.ValueTypeInt16, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getScaling(tt.attributes, tt.valueType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getScaling() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("getScaling() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScalingRead(t *testing.T) {
+	sc := &scaling{rawMin: 0, rawMax: 27648, euMin: 0, euMax: 100}
+	tests := []struct {
+		raw         int16
+		want        float64
+		wantQuality string
+	}{
+		{raw: 0, want: 0, wantQuality: qualityGood},
+		{raw: 13824, want: 50, wantQuality: qualityGood},
+		{raw: 27648, want: 100, wantQuality: qualityGood},
+		{raw: 30000, want: 100, wantQuality: qualityOverrange},
+		{raw: -100, want: 0, wantQuality: qualityUnderrange},
+		{raw: 32767, want: 100, wantQuality: qualityOverflow},
+		{raw: -32768, want: 0, wantQuality: qualityWireBreak},
+	}
+	for _, tt := range tests {
+		buffer := binary.BigEndian.AppendUint16(nil, uint16(tt.raw))
+		got, quality, err := sc.read(buffer, s7wlword)
+		if err != nil {
+			t.Fatalf("read(%d) error = %v", tt.raw, err)
+		}
+		if math.Abs(got-tt.want) > 1e-9 || quality != tt.wantQuality {
+			t.Errorf("read(%d) = %v, %s, want %v, %s", tt.raw, got, quality, tt.want, tt.wantQuality)
+		}
+	}
+
+	for value, want := range map[float64]int16{25: 6912, 100: 27648, 120: 27648, -5: 0} {
+		if got := sc.write(value, s7wlword); got != want {
+			t.Errorf("write(%v) = %v, want %d", value, got, want)
+		}
+	}
+}
+
+func TestScaledReadWrite(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	req := sdkModel.CommandRequest{
+		DeviceResourceName: "temperature",
+		Type:               common.ValueTypeFloat32,
+		Attributes:         map[string]any{"NodeName": "DB1.DBW20", EU_MIN: -50, EU_MAX: 150},
+	}
+
+	param, _ := sdkModel.NewCommandValue(req.DeviceResourceName, req.Type, float32(50))
+	if err := s.HandleWriteCommands(testDevice, protocols, []sdkModel.CommandRequest{req}, []*sdkModel.CommandValue{param}); err != nil {
+		t.Fatalf("HandleWriteCommands() error = %v", err)
+	}
+	data, _ := server.ReadArea(s7server.AreaDB, 1, 20, 2)
+	if raw := int16(binary.BigEndian.Uint16(data)); raw != 13824 {
+		t.Errorf("raw counts = %d, want 13824", raw)
+	}
+
+	_ = server.WriteArea(s7server.AreaDB, 1, 20, binary.BigEndian.AppendUint16(nil, 0x8000))
+	res, err := s.HandleReadCommands(testDevice, protocols, []sdkModel.CommandRequest{req})
+	if err != nil {
+		t.Fatalf("HandleReadCommands() error = %v", err)
+	}
+	if res[0].Value != float32(-50) || res[0].Tags[qualityTag] != qualityWireBreak {
+		t.Errorf("wire break reading = %v, tags %v", res[0].Value, res[0].Tags)
+	}
+}
+
+func TestWriteRejectsInvalidAttributes(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	tests := []struct {
+		name       string
+		valueType  string
+		value      any
+		attributes map[string]any
+	}{
+		{name: "invalid scaling", valueType: common.ValueTypeFloat32, value: float32(50),
+			attributes: map[string]any{"NodeName": "DB1.DBW20", EU_MIN: "low", EU_MAX: 150}},
+		{name: "invalid byte order", valueType: common.ValueTypeUint16, value: uint16(50),
+			attributes: map[string]any{"NodeName": "DB1.DBW20", BYTE_ORDER: "XY"}},
+		{name: "invalid encoding", valueType: common.ValueTypeUint16, value: uint16(50),
+			attributes: map[string]any{"NodeName": "DB1.DBW20", ENCODING: "EBCDIC"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := sdkModel.CommandRequest{DeviceResourceName: "value", Type: tt.valueType, Attributes: tt.attributes}
+			param, _ := sdkModel.NewCommandValue(req.DeviceResourceName, req.Type, tt.value)
+			if err := s.HandleWriteCommands(testDevice, protocols, []sdkModel.CommandRequest{req}, []*sdkModel.CommandValue{param}); err == nil {
+				t.Errorf("HandleWriteCommands() with an %s should fail", tt.name)
+			}
+			if data, _ := server.ReadArea(s7server.AreaDB, 1, 20, 2); binary.BigEndian.Uint16(data) != 0 {
+				t.Errorf("HandleWriteCommands() with an %s wrote % X", tt.name, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/spf13/cast"
+
+	"github.com/edgexfoundry/device-s7/internal/symbols"
+)
+
+// symbolTable maps the PLC symbols, like Tank1.Level or Motor1_Run, to their absolute addresses
+type symbolTable map[string]string
+
+// symbolName returns the symbol of a NodeName without the quotes of TIA Portal, like "Tank1".Level
+func symbolName(nodeName string) string {
+	return strings.ReplaceAll(strings.TrimSpace(nodeName), `"`, "")
+}
+
+// structuredType returns true for the date and time structures, which are longer than their DBD address
+// and are skipped like s7profilegen does
+func structuredType(dataType string) bool {
+	switch strings.ToUpper(dataType) {
+	case "DTL", "DATE_AND_TIME", "DT":
+		return true
+	}
+	return false
+}
+
+// loadSymbolFile loads the comma separated files of the 'SymbolFile' protocol property. The symbol tables
+// (.sdf, .seq, .asc, .csv) give the I/Q/M symbols and the DB numbers, the DB sources give the variables
+// of the DBs, named after the DB and the variable like Tank1.Level.
+func loadSymbolFile(value string) (symbolTable, error) {
+	table := make(symbolTable)
+	dbNumbers := make(map[string]int)
+	var sources []io.Reader
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s %s failed, error: %v", SYMBOL_FILE, path, err)
+		}
+		format, ok := symbols.SymbolTableFormat(path)
+		if !ok {
+			sources = append(sources, bytes.NewReader(contents))
+			continue
+		}
+		entries, err := symbols.ParseSymbolTable(bytes.NewReader(contents), format)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s %s failed, error: %v", SYMBOL_FILE, path, err)
+		}
+		for _, symbol := range entries {
+			if symbol.DataType == "DB" {
+				if number, err := strconv.Atoi(strings.TrimPrefix(symbol.Address, "DB")); err == nil {
+					dbNumbers[symbol.Name] = number
+				}
+				continue
+			}
+			// the symbols of the blocks, the peripherals, the timers, the counters and the date and time structures are not readable
+			if _, err := ParseAddress(symbol.Address); err == nil && !structuredType(symbol.DataType) {
+				table[symbol.Name] = symbol.Address
+			}
+		}
+	}
+
+	blocks, err := symbols.ParseDBSources(sources...)
+	if err != nil {
+		return nil, fmt.Errorf("parse DB sources of %s failed, error: %v", SYMBOL_FILE, err)
+	}
+	for _, block := range blocks {
+		number := block.Number
+		if number == 0 {
+			if number = dbNumbers[block.Name]; number == 0 {
+				return nil, fmt.Errorf("DB %s has no number, add the symbol table with its DB symbol to %s", block.Name, SYMBOL_FILE)
+			}
+		}
+		for _, v := range block.Variables {
+			// the strings and the date and time structures have no elementary address
+			if _, ok := symbols.DataTypeSize(v.DataType); ok && !structuredType(v.DataType) {
+				table[block.Name+"."+v.Name] = v.Address(number)
+			}
+		}
+	}
+	return table, nil
+}
+
+// resolve returns the absolute address of a symbolic NodeName, the other NodeNames are returned as they are
+func (t symbolTable) resolve(nodeName string) string {
+	if address, ok := t[symbolName(nodeName)]; ok {
+		return address
+	}
+	return nodeName
+}
+
+// unknownSymbols returns the NodeNames which are neither symbols of the table nor addresses of the device family
+func (t symbolTable) unknownSymbols(family string, nodeNames []string) []string {
+	var unknown []string
+	for _, nodeName := range nodeNames {
+		if _, ok := t[symbolName(nodeName)]; ok {
+			continue
+		}
+		address, err := translateVAddress(family, nodeName)
+		if err == nil {
+			_, err = ParseAddress(address)
+		}
+		if err != nil {
+			unknown = append(unknown, nodeName)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// loadSymbols loads the symbol file of the device and resolves the NodeNames of its profile and heartbeats,
+// the unknown symbols fail the device
+func (s *Driver) loadSymbols(deviceName string, protocols map[string]models.ProtocolProperties) error {
+	pp := protocols[Protocol]
+	value := cast.ToString(pp[SYMBOL_FILE])
+	if value == "" {
+		s.mu.Lock()
+		delete(s.symbolTables, deviceName)
+		s.mu.Unlock()
+		return nil
+	}
+	table, err := loadSymbolFile(value)
+	if err != nil {
+		s.lc.Errorf("Load symbols of device %s failed, error: %v", deviceName, err)
+		return err
+	}
+	family, err := getFamily(pp)
+	if err != nil {
+		s.lc.Errorf("Load symbols of device %s failed, error: %v", deviceName, err)
+		return err
+	}
+
+	var nodeNames []string
+	for _, key := range []string{HEARTBEAT_NODE_NAME, HOST_HEARTBEAT_NODE_NAME} {
+		if nodeName := cast.ToString(pp[key]); nodeName != "" {
+			nodeNames = append(nodeNames, nodeName)
+		}
+	}
+	if s.sdk != nil {
+		device, err := s.sdk.GetDeviceByName(deviceName)
+		if err != nil {
+			s.lc.Errorf("Load symbols of device %s failed, error: %v", deviceName, err)
+			return err
+		}
+		profile, err := s.sdk.GetProfileByName(device.ProfileName)
+		if err != nil {
+			s.lc.Errorf("Load symbols of device %s failed, error: %v", deviceName, err)
+			return err
+		}
+		for _, resource := range profile.DeviceResources {
+			if nodeName := cast.ToString(resource.Attributes["NodeName"]); nodeName != "" {
+				nodeNames = append(nodeNames, nodeName)
+			}
+		}
+	}
+	if unknown := table.unknownSymbols(family, nodeNames); len(unknown) > 0 {
+		err = fmt.Errorf("unknown symbols %s of device %s, they are not in %s %s", strings.Join(unknown, ", "), deviceName, SYMBOL_FILE, value)
+		s.lc.Errorf(err.Error())
+		return err
+	}
+
+	s.mu.Lock()
+	if s.symbolTables == nil {
+		s.symbolTables = make(map[string]symbolTable)
+	}
+	s.symbolTables[deviceName] = table
+	s.mu.Unlock()
+	s.lc.Infof("%d symbols of device %s loaded from %s", len(table), deviceName, value)
+	return nil
+}
+
+// resolveNodeName returns the absolute address of a symbolic NodeName of the device
+func (s *Driver) resolveNodeName(deviceName string, nodeName string) string {
+	s.mu.Lock()
+	table := s.symbolTables[deviceName]
+	s.mu.Unlock()
+	return table.resolve(nodeName)
+}
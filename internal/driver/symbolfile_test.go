@@ -0,0 +1,111 @@
+package driver
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+)
+
+const testDBSource = `DATA_BLOCK "Tank1"
+{ S7_Optimized_Access := 'FALSE' }
+   STRUCT
+      Level : Real;
+      Running : Bool;
+      Name : String[10];
+      Changed : DTL;
+      Started : Date_And_Time;
+   END_STRUCT;
+BEGIN
+END_DATA_BLOCK
+`
+
+const testSymbolTable = "\"Motor1_Run\",\"Q       4.0\",\"BOOL\",\"\"\n" +
+	"\"Analog\",\"PIW   256\",\"INT\",\"\"\n" +
+	"\"Stamp\",\"MD     20\",\"DATE_AND_TIME\",\"\"\n" +
+	"\"Tank1\",\"DB      1\",\"DB      1\",\"\"\n"
+
+// writeSymbolFiles writes the DB source and the symbol table, and returns the 'SymbolFile' of both
+func writeSymbolFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{"Tank1.db": testDBSource, "Symbols.sdf": testSymbolTable}
+	var paths []string
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return strings.Join(paths, ", ")
+}
+
+func TestLoadSymbolFile(t *testing.T) {
+	table, err := loadSymbolFile(writeSymbolFiles(t))
+	if err != nil {
+		t.Fatalf("loadSymbolFile() error = %v", err)
+	}
+	want := symbolTable{"Motor1_Run": "Q4.0", "Tank1.Level": "DB1.DBD0", "Tank1.Running": "DB1.DBX4.0"}
+	if len(table) != len(want) {
+		t.Errorf("loadSymbolFile() = %v, want %v", table, want)
+	}
+	for name, address := range want {
+		if table[name] != address {
+			t.Errorf("symbol %s = %s, want %s", name, table[name], address)
+		}
+	}
+	if address := table.resolve(`"Tank1".Level`); address != "DB1.DBD0" {
+		t.Errorf("resolve() of a quoted symbol = %s", address)
+	}
+	if address := table.resolve("DB2.DBW0"); address != "DB2.DBW0" {
+		t.Errorf("resolve() of an address = %s", address)
+	}
+	unknown := table.unknownSymbols(familyS7, []string{"Tank1.Level", "MW10", "Tank1.Lvl", "Motor2_Run"})
+	if strings.Join(unknown, " ") != "Motor2_Run Tank1.Lvl" {
+		t.Errorf("unknownSymbols() = %v", unknown)
+	}
+
+	// a DB named by its symbol needs the symbol table
+	path := filepath.Join(t.TempDir(), "Tank1.db")
+	if err = os.WriteFile(path, []byte(testDBSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loadSymbolFile(path); err == nil || !strings.Contains(err.Error(), "no number") {
+		t.Errorf("loadSymbolFile() of a DB without number error = %v", err)
+	}
+	if _, err = loadSymbolFile(filepath.Join(t.TempDir(), "missing.sdf")); err == nil {
+		t.Errorf("loadSymbolFile() of a missing file should fail")
+	}
+}
+
+func TestE2E_SymbolicNodeName(t *testing.T) {
+	server, s, protocols := newTestServer(t)
+	db := make([]byte, 8)
+	binary.BigEndian.PutUint32(db, math.Float32bits(2.5))
+	db[4] = 0x01
+	server.SetDB(1, db)
+
+	protocols[Protocol][SYMBOL_FILE] = writeSymbolFiles(t)
+	protocols[Protocol][HEARTBEAT_NODE_NAME] = "Tank1.Missing"
+	if err := s.loadSymbols(testDevice, protocols); err == nil || !strings.Contains(err.Error(), "Tank1.Missing") {
+		t.Fatalf("loadSymbols() of an unknown symbol error = %v", err)
+	}
+	delete(protocols[Protocol], HEARTBEAT_NODE_NAME)
+	if err := s.loadSymbols(testDevice, protocols); err != nil {
+		t.Fatalf("loadSymbols() error = %v", err)
+	}
+
+	values := readValues(t, s, protocols, []sdkModel.CommandRequest{
+		newTestRequest("level", `"Tank1".Level`, common.ValueTypeFloat32),
+		newTestRequest("running", "Tank1.Running", common.ValueTypeBool),
+	})
+	if values["level"] != float32(2.5) || values["running"] != true {
+		t.Errorf("read symbols = %v", values)
+	}
+}
@@ -0,0 +1,328 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	sdkModel "github.com/edgexfoundry/device-sdk-go/v4/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+// Well-known SZL (System Status List) IDs
+const (
+	szlModuleIdentification    = 0x0011
+	szlComponentIdentification = 0x001C
+	szlOperatingMode           = 0x0424
+	szlCommunicationCapability = 0x0131
+)
+
+// SZL first request telegram, the ID is at byte 29 and the Index at byte 31
+var szlFirstTelegram = []byte{
+	3, 0, 0, 33, 2, 240, 128, 50, 7, 0, 0, 5, 0, 0, 8, 0, 8, 0, 1, 18, 4, 17, 68, 1, 0, 255, 9, 0, 4,
+	0, 0, // ID
+	0, 0} // Index
+
+// SZL next request telegram, the sequence number is at byte 24
+var szlNextTelegram = []byte{
+	3, 0, 0, 33, 2, 240, 128, 50, 7, 0, 0, 6, 0, 0, 12, 0, 4, 0, 1, 18, 8, 18, 68, 1,
+	0, // Sequence
+	0, 0, 0, 0, 10, 0, 0, 0}
+
+// SZL is a partial list read from the PLC
+type SZL struct {
+	ID         int
+	Index      int
+	RecordSize int
+	Records    int
+	Data       []byte
+}
+
+// SZLInfo is the SZL ID and index of a device resource
+type SZLInfo struct {
+	ID    int
+	Index int
+}
+
+// getSZLInfo returns the SZL ID and index from the resource attributes, or nil for non-SZL resources
+func getSZLInfo(attributes map[string]any) (*SZLInfo, error) {
+	id, ok := attributes[SZL_ID]
+	if !ok {
+		return nil, nil
+	}
+	szlId, err := cast.ToIntE(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s %v is not an integer, error: %v", SZL_ID, id, err)
+	}
+	var szlIndex int
+	if index, ok := attributes[SZL_INDEX]; ok {
+		szlIndex, err = cast.ToIntE(index)
+		if err != nil {
+			return nil, fmt.Errorf("%s %v is not an integer, error: %v", SZL_INDEX, index, err)
+		}
+	}
+	if szlId < 0 || szlId > 0xFFFF || szlIndex < 0 || szlIndex > 0xFFFF {
+		return nil, fmt.Errorf("SZL ID 0x%X or index 0x%X is out of range", szlId, szlIndex)
+	}
+	return &SZLInfo{ID: szlId, Index: szlIndex}, nil
+}
+
+// splitRequests separates the requests having the attribute from the data area requests
+func splitRequests(reqs []sdkModel.CommandRequest, attribute string) (dataReqs []sdkModel.CommandRequest, attrReqs []sdkModel.CommandRequest) {
+	for _, req := range reqs {
+		if _, ok := req.Attributes[attribute]; ok {
+			attrReqs = append(attrReqs, req)
+		} else {
+			dataReqs = append(dataReqs, req)
+		}
+	}
+	return dataReqs, attrReqs
+}
+
+// readSZLCommands reads the SZL resources one by one, the failed ones are logged and skipped
+func (s *Driver) readSZLCommands(deviceName string, protocols map[string]models.ProtocolProperties, reqs []sdkModel.CommandRequest) (res []*sdkModel.CommandValue) {
+	for _, req := range reqs {
+		szlInfo, err := getSZLInfo(req.Attributes)
+		if err != nil {
+			s.lc.Errorf("invalid SZL attributes of resource %s, error: %v", req.DeviceResourceName, err)
+			continue
+		}
+
+		var szl *SZL
+		s7Client := s.getS7Client(deviceName, protocols)
+		// read SZL from S7 device, if error, try 'RetryCount' times
+		for retrytimes := s.getConfig().retryCount; retrytimes > 0; retrytimes-- {
+			szl, err = s7Client.ReadSZL(szlInfo.ID, szlInfo.Index)
+			if err == nil {
+				// the errors may be item errors, only the success is recorded in the connection state
+				s.updateConnectionState(deviceName, nil)
+				break
+			}
+			s.lc.Errorf("ReadSZL 0x%04X Error: %s, reconnecting...", szlInfo.ID, err)
+			s7Client = s.reconnect(deviceName, protocols)
+		}
+		if err != nil {
+			continue
+		}
+
+		var value any
+		switch req.Type {
+		case common.ValueTypeBinary:
+			value = szl.Data
+		case common.ValueTypeString:
+			value = base64.StdEncoding.EncodeToString(szl.Data)
+		case common.ValueTypeObject:
+			value = decodeSZL(szl)
+		default:
+			s.lc.Errorf("SZL resource %s should be Binary, String or Object, not %s", req.DeviceResourceName, req.Type)
+			continue
+		}
+
+		result, err := sdkModel.NewCommandValue(req.DeviceResourceName, req.Type, value)
+		if err != nil {
+			s.lc.Errorf("getCommandValue error: %v", err)
+			continue
+		}
+		result.Origin = time.Now().UnixNano()
+		res = append(res, result)
+	}
+	return res
+}
+
+// ReadSZL reads a partial list of the System Status List
+func (c *S7Client) ReadSZL(id int, index int) (*SZL, error) {
+	if c == nil || c.Handler == nil {
+		return nil, fmt.Errorf("S7 client is not connected")
+	}
+
+	request := make([]byte, len(szlFirstTelegram))
+	copy(request, szlFirstTelegram)
+	binary.BigEndian.PutUint16(request[29:], uint16(id))
+	binary.BigEndian.PutUint16(request[31:], uint16(index))
+
+	szl := &SZL{ID: id, Index: index}
+	first := true
+	for {
+		response, err := c.Handler.Send(request)
+		if err != nil {
+			return nil, err
+		}
+		if len(response) < 33 {
+			return nil, fmt.Errorf("invalid SZL response size %d", len(response))
+		}
+		if binary.BigEndian.Uint16(response[27:]) != 0 || response[29] != 0xFF {
+			return nil, fmt.Errorf("SZL 0x%04X index 0x%04X is not available, error code: 0x%04X, return code: 0x%02X",
+				id, index, binary.BigEndian.Uint16(response[27:]), response[29])
+		}
+		size := int(binary.BigEndian.Uint16(response[31:]))
+		data := response[33:]
+		if size > len(data) {
+			return nil, fmt.Errorf("SZL data size %d exceeds the response size %d", size, len(data))
+		}
+		data = data[:size]
+		if first {
+			// SZL-ID, Index, LENTHDR and N_DR precede the records
+			if len(data) < 8 {
+				return nil, fmt.Errorf("invalid SZL header size %d", len(data))
+			}
+			szl.RecordSize = int(binary.BigEndian.Uint16(data[4:]))
+			szl.Records = int(binary.BigEndian.Uint16(data[6:]))
+			data = data[8:]
+			first = false
+		}
+		szl.Data = append(szl.Data, data...)
+
+		// the last data unit flag is 0x00
+		if response[26] == 0x00 {
+			break
+		}
+		sequence := response[24]
+		request = make([]byte, len(szlNextTelegram))
+		copy(request, szlNextTelegram)
+		request[24] = sequence
+	}
+	return szl, nil
+}
+
+// decodeSZL decodes the well-known SZL IDs, the others are returned as Base64
+func decodeSZL(szl *SZL) map[string]any {
+	result := map[string]any{
+		"szlId": fmt.Sprintf("0x%04X", szl.ID),
+		"index": fmt.Sprintf("0x%04X", szl.Index),
+	}
+	var records []map[string]any
+	switch szl.ID {
+	case szlModuleIdentification:
+		records = decodeSZLRecords(szl, 28, decodeModuleIdentification)
+	case szlComponentIdentification:
+		records = decodeSZLRecords(szl, 34, decodeComponentIdentification)
+	case szlOperatingMode:
+		records = decodeSZLRecords(szl, 20, decodeOperatingMode)
+	case szlCommunicationCapability:
+		records = decodeSZLRecords(szl, 8, decodeCommunicationCapability)
+	}
+	if records == nil {
+		result["data"] = base64.StdEncoding.EncodeToString(szl.Data)
+		return result
+	}
+	result["records"] = records
+	return result
+}
+
+// decodeSZLRecords splits the SZL data into records and decodes each of them
+func decodeSZLRecords(szl *SZL, minSize int, decode func(record []byte) map[string]any) []map[string]any {
+	size := szl.RecordSize
+	if size < minSize {
+		return nil
+	}
+	records := []map[string]any{}
+	for offset := 0; offset+size <= len(szl.Data); offset += size {
+		records = append(records, decode(szl.Data[offset:offset+size]))
+	}
+	return records
+}
+
+// SZL 0x0011, index, MlfB (order number), BGTyp, Ausbg, Ausbe
+func decodeModuleIdentification(record []byte) map[string]any {
+	index := binary.BigEndian.Uint16(record[0:])
+	result := map[string]any{
+		"index":       index,
+		"orderNumber": strings.TrimSpace(string(record[2:22])),
+		"moduleType":  binary.BigEndian.Uint16(record[22:]),
+	}
+	// hardware (0x0006) and firmware (0x0007) carry 'V' and three version digits
+	if (index == 0x0006 || index == 0x0007) && record[24] == 'V' {
+		result["version"] = fmt.Sprintf("V%d.%d.%d", record[25], record[26], record[27])
+	} else {
+		result["version"] = fmt.Sprintf("%d.%d", binary.BigEndian.Uint16(record[24:]), binary.BigEndian.Uint16(record[26:]))
+	}
+	return result
+}
+
+var componentNames = map[uint16]string{
+	0x0001: "automationSystemName",
+	0x0002: "moduleName",
+	0x0003: "plantDesignation",
+	0x0004: "copyright",
+	0x0005: "serialNumber",
+	0x0007: "moduleTypeName",
+	0x0008: "memoryCardSerialNumber",
+	0x0009: "manufacturer",
+	0x000A: "oemId",
+	0x000B: "locationDesignation",
+}
+
+// SZL 0x001C, index and a 32 characters name
+func decodeComponentIdentification(record []byte) map[string]any {
+	index := binary.BigEndian.Uint16(record[0:])
+	name, ok := componentNames[index]
+	if !ok {
+		name = fmt.Sprintf("component%d", index)
+	}
+	value := strings.TrimSpace(strings.TrimRight(string(record[2:34]), "\x00"))
+	if index == 0x0009 {
+		// manufacturer ID, profile ID and profile specific type
+		value = fmt.Sprintf("0x%04X", binary.BigEndian.Uint16(record[2:]))
+	}
+	return map[string]any{
+		"index": index,
+		"name":  name,
+		"value": value,
+	}
+}
+
+var operatingModes = map[byte]string{
+	0x00: "UNKNOWN",
+	0x01: "STOP",
+	0x02: "STOP",
+	0x03: "STOP",
+	0x04: "STOP",
+	0x05: "STARTUP",
+	0x06: "STARTUP",
+	0x07: "STARTUP",
+	0x08: "RUN",
+	0x09: "RUN",
+	0x0A: "HOLD",
+	0x0D: "DEFECT",
+}
+
+// SZL 0x0424, event ID and the current/previous operating mode
+func decodeOperatingMode(record []byte) map[string]any {
+	current := record[3] & 0x0F
+	previous := record[3] >> 4
+	return map[string]any{
+		"eventId":      fmt.Sprintf("0x%04X", binary.BigEndian.Uint16(record[0:])),
+		"mode":         operatingModes[current],
+		"modeCode":     current,
+		"previousMode": operatingModes[previous],
+	}
+}
+
+// SZL 0x0131, index 1 is the general communication data, the others are kept as Base64
+func decodeCommunicationCapability(record []byte) map[string]any {
+	index := binary.BigEndian.Uint16(record[0:])
+	if index != 0x0001 || len(record) < 14 {
+		return map[string]any{
+			"index": index,
+			"data":  base64.StdEncoding.EncodeToString(record),
+		}
+	}
+	return map[string]any{
+		"index":          index,
+		"maxPduLength":   binary.BigEndian.Uint16(record[2:]),
+		"maxConnections": binary.BigEndian.Uint16(record[4:]),
+		"maxMpiRate":     binary.BigEndian.Uint32(record[6:]),
+		"maxBusRate":     binary.BigEndian.Uint32(record[10:]),
+	}
+}
@@ -0,0 +1,128 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+)
+
+// szlRecords joins the records of a SZL partial list
+func szlRecords(records ...[]byte) []byte {
+	var data []byte
+	for _, record := range records {
+		data = append(data, record...)
+	}
+	return data
+}
+
+// moduleRecord returns a SZL 0x0011 record of a CPU 315-2 PN/DP
+func moduleRecord(index uint16, version [4]byte) []byte {
+	record := []byte{byte(index >> 8), byte(index)}
+	record = append(record, "6ES7 315-2EH14-0AB0 "...)
+	record = append(record, 0x00, 0xC0)
+	return append(record, version[:]...)
+}
+
+// componentRecord returns a SZL 0x001C record, the name is padded with zeros
+func componentRecord(index uint16, name string) []byte {
+	record := make([]byte, 34)
+	record[0], record[1] = byte(index>>8), byte(index)
+	copy(record[2:], name)
+	return record
+}
+
+// operatingModeRecord returns a SZL 0x0424 record
+func operatingModeRecord(event uint16, modes byte) []byte {
+	record := make([]byte, 20)
+	record[0], record[1] = byte(event>>8), byte(event)
+	record[2] = 0xFF
+	record[3] = modes
+	return record
+}
+
+func TestDecodeSZL(t *testing.T) {
+	tests := []struct {
+		name    string
+		szl     *SZL
+		records []map[string]any
+	}{
+		{
+			name: "CPU 315 module identification",
+			szl: &SZL{ID: szlModuleIdentification, RecordSize: 28, Records: 3, Data: szlRecords(
+				moduleRecord(0x0001, [4]byte{0x00, 0x01, 0x00, 0x01}),
+				moduleRecord(0x0006, [4]byte{0x00, 0x01, 0x00, 0x01}),
+				moduleRecord(0x0007, [4]byte{'V', 3, 2, 6}),
+			)},
+			records: []map[string]any{
+				{"index": uint16(0x0001), "orderNumber": "6ES7 315-2EH14-0AB0", "moduleType": uint16(0x00C0), "version": "1.1"},
+				{"index": uint16(0x0006), "orderNumber": "6ES7 315-2EH14-0AB0", "moduleType": uint16(0x00C0), "version": "1.1"},
+				{"index": uint16(0x0007), "orderNumber": "6ES7 315-2EH14-0AB0", "moduleType": uint16(0x00C0), "version": "V3.2.6"},
+			},
+		},
+		{
+			name: "component identification",
+			szl: &SZL{ID: szlComponentIdentification, RecordSize: 34, Records: 4, Data: szlRecords(
+				componentRecord(0x0001, "SIMATIC 300(1)"),
+				componentRecord(0x0002, "CPU 315-2 PN/DP"),
+				componentRecord(0x0005, "S C-C2UR28922012"),
+				componentRecord(0x0009, "\x00\x2A\xF6\x00"),
+			)},
+			records: []map[string]any{
+				{"index": uint16(0x0001), "name": "automationSystemName", "value": "SIMATIC 300(1)"},
+				{"index": uint16(0x0002), "name": "moduleName", "value": "CPU 315-2 PN/DP"},
+				{"index": uint16(0x0005), "name": "serialNumber", "value": "S C-C2UR28922012"},
+				{"index": uint16(0x0009), "name": "manufacturer", "value": "0x002A"},
+			},
+		},
+		{
+			name: "operating mode RUN",
+			szl:  &SZL{ID: szlOperatingMode, RecordSize: 20, Records: 1, Data: operatingModeRecord(0x5144, 0x58)},
+			records: []map[string]any{
+				{"eventId": "0x5144", "mode": "RUN", "modeCode": byte(0x08), "previousMode": "STARTUP"},
+			},
+		},
+		{
+			name: "operating mode STOP",
+			szl:  &SZL{ID: szlOperatingMode, RecordSize: 20, Records: 1, Data: operatingModeRecord(0x4303, 0x84)},
+			records: []map[string]any{
+				{"eventId": "0x4303", "mode": "STOP", "modeCode": byte(0x04), "previousMode": "RUN"},
+			},
+		},
+		{
+			name: "communication capability",
+			szl: &SZL{ID: szlCommunicationCapability, Index: 0x0001, RecordSize: 40, Records: 1, Data: append(
+				[]byte{0x00, 0x01, 0x00, 0xF0, 0x00, 0x10, 0x00, 0x02, 0xDC, 0x6C, 0x00, 0xB7, 0x1B, 0x00},
+				make([]byte, 26)...)},
+			records: []map[string]any{
+				{"index": uint16(0x0001), "maxPduLength": uint16(240), "maxConnections": uint16(16), "maxMpiRate": uint32(187500), "maxBusRate": uint32(12000000)},
+			},
+		},
+		{
+			name: "other communication capability",
+			szl:  &SZL{ID: szlCommunicationCapability, Index: 0x0002, RecordSize: 8, Records: 1, Data: []byte{0x00, 0x02, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06}},
+			records: []map[string]any{
+				{"index": uint16(0x0002), "data": "AAIBAgMEBQY="},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := decodeSZL(tt.szl)
+			if !reflect.DeepEqual(result["records"], tt.records) {
+				t.Errorf("decodeSZL() records = %v, want %v", result["records"], tt.records)
+			}
+		})
+	}
+}
+
+func TestDecodeSZLUnknown(t *testing.T) {
+	// unknown IDs and records too short for their ID are returned as Base64
+	for _, szl := range []*SZL{
+		{ID: 0x0F00, RecordSize: 2, Records: 1, Data: []byte{0x01, 0x02}},
+		{ID: szlOperatingMode, RecordSize: 2, Records: 1, Data: []byte{0x01, 0x02}},
+	} {
+		result := decodeSZL(szl)
+		if result["records"] != nil || result["data"] != "AQI=" {
+			t.Errorf("decodeSZL(0x%04X) = %v, want the Base64 data", szl.ID, result)
+		}
+	}
+}
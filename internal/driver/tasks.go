@@ -0,0 +1,86 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+)
+
+// startDeviceTasks starts the background tasks enabled by the device protocol properties
+func (s *Driver) startDeviceTasks(deviceName string, protocols map[string]models.ProtocolProperties) {
+	s.stopDeviceTasks(deviceName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pp := protocols[Protocol]
+
+	if interval, err := getBlockMonitorInterval(pp); err != nil {
+		s.lc.Errorf("Block monitor of device %s is not started, error: %v", deviceName, err)
+	} else if interval > 0 {
+		go s.monitorBlocks(ctx, deviceName, protocols, interval)
+	}
+
+	if interval, integrityInterval, err := getPollIntervals(pp); err != nil {
+		s.lc.Errorf("Change-of-value poller of device %s is not started, error: %v", deviceName, err)
+	} else if interval > 0 {
+		if resources, err := s.getCOVResources(deviceName); err != nil {
+			s.lc.Errorf("Change-of-value poller of device %s is not started, error: %v", deviceName, err)
+		} else if len(resources) > 0 {
+			go s.pollChanges(ctx, deviceName, protocols, resources, interval, integrityInterval)
+		}
+	}
+
+	if interval, err := getKeepAliveInterval(pp); err != nil {
+		s.lc.Errorf("Keepalive of device %s is not started, error: %v", deviceName, err)
+	} else if interval > 0 {
+		go s.keepAlive(ctx, deviceName, protocols, interval)
+	}
+
+	if config, err := getHeartbeatConfig(pp); err != nil {
+		s.lc.Errorf("Heartbeats of device %s are not started, error: %v", deviceName, err)
+	} else {
+		if config.NodeName != "" {
+			go s.superviseHeartbeat(ctx, deviceName, protocols, config)
+		}
+		if config.HostNodeName != "" {
+			go s.writeHostHeartbeat(ctx, deviceName, protocols, config)
+		}
+	}
+
+	s.mu.Lock()
+	s.tasks[deviceName] = cancel
+	if s.taskContexts == nil {
+		s.taskContexts = make(map[string]context.Context)
+	}
+	s.taskContexts[deviceName] = ctx
+	s.mu.Unlock()
+}
+
+// stopDeviceTasks stops all the background tasks of the device
+func (s *Driver) stopDeviceTasks(deviceName string) {
+	s.mu.Lock()
+	cancel, ok := s.tasks[deviceName]
+	delete(s.tasks, deviceName)
+	delete(s.taskContexts, deviceName)
+	s.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+// deviceContext returns the context of the background tasks of the device, cancelled when they are stopped,
+// for the async values sent by the commands. A device without tasks, like in the tests, never cancels them.
+func (s *Driver) deviceContext(deviceName string) context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ctx, ok := s.taskContexts[deviceName]; ok {
+		return ctx
+	}
+	return context.Background()
+}
@@ -29,4 +29,5 @@ type S7Info struct {
 type S7Client struct {
 	DeviceName string
 	Client     gos7.Client
+	Handler    *gos7.TCPClientHandler
 }
@@ -0,0 +1,177 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"github.com/spf13/cast"
+)
+
+// addressRange is the bytes of a resource in an S7 area
+type addressRange struct {
+	resource string
+	nodeName string
+	area     int
+	dbNumber int
+	start    int
+	size     int
+}
+
+// overlaps returns true when the ranges share bytes without being the same address and size,
+// which are aliases of the same value like a raw and a scaled resource
+func (r addressRange) overlaps(other addressRange) bool {
+	if r.area != other.area || r.dbNumber != other.dbNumber {
+		return false
+	}
+	if r.start == other.start && r.size == other.size {
+		return false
+	}
+	return r.start < other.start+other.size && other.start < r.start+r.size
+}
+
+// resourceSize returns the number of bytes a resource reads at its address, 0 for bits,
+// or an error when the address width doesn't fit the value type
+func resourceSize(resource models.DeviceResource, dbInfo *DBInfo) (int, error) {
+	valueType := resource.Properties.ValueType
+	attributes := resource.Attributes
+	width := valueSize(dbInfo.WordLength, "")
+
+	if _, ok := attributes[ALARM_WORDS]; ok {
+		state, err := newAlarmState(attributes)
+		if err != nil {
+			return 0, err
+		}
+		if dbInfo.Area != 0x84 || (dbInfo.WordLength == s7wlbit && dbInfo.Start&0x07 != 0) {
+			return 0, fmt.Errorf("alarm words should start at a byte of a DB")
+		}
+		return state.words * 2, nil
+	}
+	if _, err := getByteOrder(attributes); err != nil {
+		return 0, err
+	}
+	sc, err := getScaling(attributes, valueType)
+	if err != nil {
+		return 0, err
+	}
+	encoding, err := getEncoding(attributes, valueType)
+	if err != nil {
+		return 0, err
+	}
+
+	var want []int
+	switch {
+	case encoding == encodingS5Time:
+		want = []int{2}
+	case sc != nil || encoding != "":
+		// the raw counts and the encoded values take the width of the address
+		want = []int{1, 2, 4}
+	case valueType == common.ValueTypeBool:
+		want = []int{0}
+	case valueType == common.ValueTypeUint8 || valueType == common.ValueTypeInt8:
+		want = []int{1}
+	case valueType == common.ValueTypeUint16 || valueType == common.ValueTypeInt16:
+		want = []int{2}
+	case valueType == common.ValueTypeUint32 || valueType == common.ValueTypeInt32 || valueType == common.ValueTypeFloat32:
+		want = []int{4}
+	case is64Bit(valueType):
+		// 8 bytes from the start of a double word address
+		want = []int{4}
+	case valueType == common.ValueTypeString:
+		want = []int{1, 2, 4}
+	default:
+		return 0, fmt.Errorf("value type %s can't be read from an S7 address", valueType)
+	}
+	for _, size := range want {
+		if size == width {
+			return valueSize(dbInfo.WordLength, rawValueType(valueType, sc != nil, encoding)), nil
+		}
+	}
+	names := map[int]string{0: "bit", 1: "byte", 2: "word", 4: "double word"}
+	return 0, fmt.Errorf("value type %s doesn't fit the %s address", valueType, names[width])
+}
+
+// validateResources validates the NodeNames of the resources against the address syntax of the device family
+// and the symbols of the device, the address widths against the value types, and the overlapping addresses.
+// All the violations are reported in a single error.
+func validateResources(resources []models.DeviceResource, family string, table symbolTable) error {
+	var violations []string
+	var ranges []addressRange
+	for _, resource := range resources {
+		nodeName := cast.ToString(resource.Attributes["NodeName"])
+		if nodeName == "" {
+			continue
+		}
+		address, err := translateVAddress(family, table.resolve(nodeName))
+		var dbInfo *DBInfo
+		if err == nil {
+			dbInfo, err = ParseAddress(address)
+		}
+		if err != nil {
+			reason := "invalid NodeName " + nodeName
+			if len(table) > 0 {
+				reason = nodeName + " is neither a symbol nor an address"
+			}
+			violations = append(violations, fmt.Sprintf("%s: %s, %v", resource.Name, reason, err))
+			continue
+		}
+		size, err := resourceSize(resource, dbInfo)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %s, %v", resource.Name, nodeName, err))
+			continue
+		}
+		// bits may be read from the bytes and words of other resources, like the bits of a status word
+		if size == 0 {
+			continue
+		}
+		r := addressRange{resource: resource.Name, nodeName: nodeName, area: dbInfo.Area, dbNumber: dbInfo.DBNumber, start: dbInfo.Start, size: size}
+		for _, other := range ranges {
+			if r.overlaps(other) {
+				violations = append(violations, fmt.Sprintf("%s: %s overlaps %s of resource %s", r.resource, r.nodeName, other.nodeName, other.resource))
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d violations: %s", len(violations), strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// validateDeviceProfile validates the resources of the profile of a device, with its symbols
+func (s *Driver) validateDeviceProfile(deviceName string, protocols map[string]models.ProtocolProperties) error {
+	if s.sdk == nil {
+		return nil
+	}
+	device, err := s.sdk.GetDeviceByName(deviceName)
+	if err != nil {
+		s.lc.Errorf("Validate profile of device %s failed, error: %v", deviceName, err)
+		return err
+	}
+	profile, err := s.sdk.GetProfileByName(device.ProfileName)
+	if err != nil {
+		s.lc.Errorf("Validate profile of device %s failed, error: %v", deviceName, err)
+		return err
+	}
+	family, err := getFamily(protocols[Protocol])
+	if err != nil {
+		s.lc.Errorf("Validate profile of device %s failed, error: %v", deviceName, err)
+		return err
+	}
+	s.mu.Lock()
+	table := s.symbolTables[deviceName]
+	s.mu.Unlock()
+	if err = validateResources(profile.DeviceResources, family, table); err != nil {
+		err = fmt.Errorf("profile %s of device %s has %v", profile.Name, deviceName, err)
+		s.lc.Errorf(err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package driver
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+	"gopkg.in/yaml.v3"
+)
+
+func newTestResource(name string, nodeName string, valueType string, attributes ...any) models.DeviceResource {
+	resource := models.DeviceResource{
+		Name:       name,
+		Properties: models.ResourceProperties{ValueType: valueType, ReadWrite: common.ReadWrite_RW},
+		Attributes: map[string]any{"NodeName": nodeName},
+	}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		resource.Attributes[attributes[i].(string)] = attributes[i+1]
+	}
+	return resource
+}
+
+func TestValidateResources(t *testing.T) {
+	valid := []models.DeviceResource{
+		newTestResource("status", "DB1.DBW0", common.ValueTypeUint16),
+		newTestResource("ready", "DB1.DBX0.3", common.ValueTypeBool),
+		newTestResource("level", "DB1.DBD2", common.ValueTypeFloat32),
+		newTestResource("total", "DB1.DBD6", common.ValueTypeFloat64),
+		newTestResource("raw", "DB1.DBW14", common.ValueTypeInt16),
+		newTestResource("temperature", "DB1.DBW14", common.ValueTypeFloat32, EU_MAX, 200),
+		newTestResource("delay", "DB1.DBW16", common.ValueTypeInt32, ENCODING, "S5TIME"),
+		newTestResource("alarms", "DB2.DBW0", common.ValueTypeObject, ALARM_WORDS, 2),
+		newTestResource("level2", "DB2.DBD4", common.ValueTypeFloat32),
+		newTestResource("speed", "MW0", common.ValueTypeInt16),
+		newTestResource("motor", "Motor1_Run", common.ValueTypeBool),
+		newTestResource("info", "", common.ValueTypeObject, SZL_ID, "0x0011"),
+	}
+	table := symbolTable{"Motor1_Run": "Q4.0"}
+	if err := validateResources(valid, familyS7, table); err != nil {
+		t.Errorf("validateResources() error = %v", err)
+	}
+
+	invalid := []models.DeviceResource{
+		newTestResource("typo", "DB4.DBW2x", common.ValueTypeInt16),
+		newTestResource("narrow", "DB4.DBW4", common.ValueTypeFloat32),
+		newTestResource("bit", "DB4.DBB6", common.ValueTypeBool),
+		newTestResource("dword", "DB4.DBD8", common.ValueTypeUint32),
+		newTestResource("word", "DB4.DBW10", common.ValueTypeInt16),
+		newTestResource("symbol", "Motor2_Run", common.ValueTypeBool),
+		newTestResource("alarms", "DB4.DBX20.1", common.ValueTypeObject, ALARM_WORDS, 1),
+		newTestResource("delay", "DB4.DBW30", common.ValueTypeInt16, ENCODING, "S5TIME"),
+		newTestResource("timeout", "DB4.DBD32", common.ValueTypeInt64, ENCODING, "S5TIME"),
+	}
+	err := validateResources(invalid, familyS7, table)
+	if err == nil {
+		t.Fatalf("validateResources() of invalid resources should fail")
+	}
+	for _, want := range []string{"8 violations", "typo: DB4.DBW2x is neither a symbol nor an address", "narrow: DB4.DBW4, value type Float32 doesn't fit the word address",
+		"bit: DB4.DBB6, value type Bool doesn't fit the byte address", "word: DB4.DBW10 overlaps DB4.DBD8 of resource dword",
+		"symbol: Motor2_Run is neither a symbol nor an address", "alarms: DB4.DBX20.1, alarm words should start at a byte",
+		"delay: DB4.DBW30, S5TIME is supported by 32 and 64-bit integer and float resources only, not Int16",
+		"timeout: DB4.DBD32, value type Int64 doesn't fit the double word address"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("validateResources() error = %v, want %q", err, want)
+		}
+	}
+}
+
+func TestValidateSampleProfile(t *testing.T) {
+	contents, err := os.ReadFile("../../cmd/res/profiles/Simple-Driver.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var profile dtos.DeviceProfile
+	if err = yaml.Unmarshal(contents, &profile); err != nil {
+		t.Fatal(err)
+	}
+	if err = validateResources(dtos.ToDeviceProfileModel(profile).DeviceResources, familyS7, nil); err != nil {
+		t.Errorf("validateResources() of the sample profile error = %v", err)
+	}
+}
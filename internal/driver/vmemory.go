@@ -0,0 +1,142 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v4/models"
+
+	"github.com/spf13/cast"
+)
+
+// Device families, the V memory of LOGO! and S7-200 is DB1
+const (
+	familyS7       = "S7"
+	familyLOGO     = "LOGO"
+	familyLOGO0BA7 = "LOGO-0BA7"
+	familyS7200    = "S7-200"
+)
+
+// vMemoryDB is the DB which holds the V memory of LOGO! and S7-200
+const vMemoryDB = 1
+
+// logoArea is the V memory range of a LOGO! I/Q/M area
+type logoArea struct {
+	Start int  // first byte of the area
+	Count int  // number of bits or words
+	Word  bool // analog areas are words
+}
+
+// LOGO! 0BA8 and later
+var logoAreas = map[string]logoArea{
+	"I":   {Start: 1024, Count: 24},
+	"AI":  {Start: 1032, Count: 8, Word: true},
+	"Q":   {Start: 1064, Count: 20},
+	"AQ":  {Start: 1072, Count: 8, Word: true},
+	"M":   {Start: 1104, Count: 64},
+	"AM":  {Start: 1118, Count: 64, Word: true},
+	"NI":  {Start: 1246, Count: 64},
+	"NAI": {Start: 1262, Count: 32, Word: true},
+	"NQ":  {Start: 1390, Count: 64},
+	"NAQ": {Start: 1406, Count: 16, Word: true},
+}
+
+// LOGO! 0BA7
+var logo0BA7Areas = map[string]logoArea{
+	"I":  {Start: 923, Count: 24},
+	"AI": {Start: 926, Count: 8, Word: true},
+	"Q":  {Start: 942, Count: 16},
+	"AQ": {Start: 944, Count: 2, Word: true},
+	"M":  {Start: 948, Count: 27},
+	"AM": {Start: 952, Count: 16, Word: true},
+}
+
+var (
+	vAddressPattern    = regexp.MustCompile(`^V([XBWD]?)(\d+)(?:\.(\d+))?$`)
+	logoAddressPattern = regexp.MustCompile(`^(NAI|NAQ|NI|NQ|AI|AQ|AM|I|Q|M)(\d+)$`)
+)
+
+// getFamily returns the device family from the protocol properties
+func getFamily(pp models.ProtocolProperties) (string, error) {
+	family := strings.ToUpper(cast.ToString(pp[FAMILY]))
+	switch family {
+	case "":
+		return familyS7, nil
+	case familyS7, familyLOGO, familyLOGO0BA7, familyS7200:
+		return family, nil
+	default:
+		return "", fmt.Errorf("%s %s should be S7, LOGO, LOGO-0BA7 or S7-200", FAMILY, family)
+	}
+}
+
+// getFamilyDBInfo transfers the NodeName to DBInfo by the symbols of the device and the address syntax of the device family
+func (s *Driver) getFamilyDBInfo(deviceName string, family string, variable string) (*DBInfo, error) {
+	nodeName, err := translateVAddress(family, s.resolveNodeName(deviceName, variable))
+	if err != nil {
+		s.lc.Errorf("convert %s address %s failed, err: %v", family, variable, err)
+		return nil, err
+	}
+	return s.getDBInfo(nodeName)
+}
+
+// translateVAddress translates the V memory and LOGO! addresses to DB1 addresses, the DB addresses like DB1.DBW2
+// and the addresses of S7 family are returned as they are, as well as the other S7-200 addresses
+func translateVAddress(family string, variable string) (string, error) {
+	if family != familyLOGO && family != familyLOGO0BA7 && family != familyS7200 {
+		return variable, nil
+	}
+	address := strings.ToUpper(strings.ReplaceAll(variable, " ", ""))
+
+	if match := vAddressPattern.FindStringSubmatch(address); match != nil {
+		return vToDBAddress(match[1], match[2], match[3])
+	}
+
+	if family == familyS7200 || strings.HasPrefix(address, "DB") {
+		return variable, nil
+	}
+	areas := logoAreas
+	if family == familyLOGO0BA7 {
+		areas = logo0BA7Areas
+	}
+	match := logoAddressPattern.FindStringSubmatch(address)
+	if match == nil {
+		return "", fmt.Errorf("%s is not a V memory, DB or LOGO! address", variable)
+	}
+	area, ok := areas[match[1]]
+	number, _ := strconv.Atoi(match[2])
+	if !ok || number < 1 || number > area.Count {
+		return "", fmt.Errorf("%s is out of the %s address range", variable, family)
+	}
+	if area.Word {
+		return fmt.Sprintf("DB%d.DBW%d", vMemoryDB, area.Start+(number-1)*2), nil
+	}
+	return fmt.Sprintf("DB%d.DBX%d.%d", vMemoryDB, area.Start+(number-1)/8, (number-1)%8), nil
+}
+
+// vToDBAddress translates V10.3, VB10, VW10 and VD10 to the DB1 address
+func vToDBAddress(size string, offset string, bit string) (string, error) {
+	if bit != "" {
+		if size != "" && size != "X" {
+			return "", fmt.Errorf("V%s%s.%s should not have a bit", size, offset, bit)
+		}
+		return fmt.Sprintf("DB%d.DBX%s.%s", vMemoryDB, offset, bit), nil
+	}
+	switch size {
+	case "", "B":
+		return fmt.Sprintf("DB%d.DBB%s", vMemoryDB, offset), nil
+	case "W":
+		return fmt.Sprintf("DB%d.DBW%s", vMemoryDB, offset), nil
+	case "D":
+		return fmt.Sprintf("DB%d.DBD%s", vMemoryDB, offset), nil
+	default:
+		return "", fmt.Errorf("V%s%s should have a bit", size, offset)
+	}
+}
@@ -0,0 +1,51 @@
+package driver
+
+import (
+	"testing"
+)
+
+func TestTranslateVAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		family   string
+		variable string
+		want     string
+		wantErr  bool
+	}{
+		{name: "S7 keeps the address", family: familyS7, variable: "DB4.DBW2", want: "DB4.DBW2"},
+		{name: "V bit", family: familyS7200, variable: "V10.3", want: "DB1.DBX10.3"},
+		{name: "V byte", family: familyS7200, variable: "V10", want: "DB1.DBB10"},
+		{name: "VB", family: familyLOGO, variable: "vb12", want: "DB1.DBB12"},
+		{name: "VW", family: familyLOGO, variable: "VW100", want: "DB1.DBW100"},
+		{name: "VD", family: familyS7200, variable: "VD200", want: "DB1.DBD200"},
+		{name: "VW with bit", family: familyS7200, variable: "VW10.1", wantErr: true},
+		{name: "S7-200 keeps the DB address", family: familyS7200, variable: "DB1.DBW2", want: "DB1.DBW2"},
+		{name: "LOGO! keeps the DB address", family: familyLOGO, variable: "DB1.DBW2", want: "DB1.DBW2"},
+		{name: "LOGO! 0BA7 keeps the DB address", family: familyLOGO0BA7, variable: "DB1.DBX10.3", want: "DB1.DBX10.3"},
+		{name: "LOGO! I1", family: familyLOGO, variable: "I1", want: "DB1.DBX1024.0"},
+		{name: "LOGO! I10", family: familyLOGO, variable: "I10", want: "DB1.DBX1025.1"},
+		{name: "LOGO! Q1", family: familyLOGO, variable: "Q1", want: "DB1.DBX1064.0"},
+		{name: "LOGO! M9", family: familyLOGO, variable: "M9", want: "DB1.DBX1105.0"},
+		{name: "LOGO! AI2", family: familyLOGO, variable: "AI2", want: "DB1.DBW1034"},
+		{name: "LOGO! AQ1", family: familyLOGO, variable: "AQ1", want: "DB1.DBW1072"},
+		{name: "LOGO! AM3", family: familyLOGO, variable: "AM3", want: "DB1.DBW1122"},
+		{name: "LOGO! 0BA7 I1", family: familyLOGO0BA7, variable: "I1", want: "DB1.DBX923.0"},
+		{name: "LOGO! 0BA7 Q9", family: familyLOGO0BA7, variable: "Q9", want: "DB1.DBX943.0"},
+		{name: "LOGO! 0BA7 AI1", family: familyLOGO0BA7, variable: "AI1", want: "DB1.DBW926"},
+		{name: "LOGO! 0BA7 has no NI", family: familyLOGO0BA7, variable: "NI1", wantErr: true},
+		{name: "LOGO! I0", family: familyLOGO, variable: "I0", wantErr: true},
+		{name: "LOGO! I25", family: familyLOGO, variable: "I25", wantErr: true},
+		{name: "LOGO! invalid", family: familyLOGO, variable: "X1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := translateVAddress(tt.family, tt.variable)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("translateVAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("translateVAddress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,703 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package s7server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// COTP PDU types
+const (
+	cotpConnectionRequest = 0xE0
+	cotpConnectionConfirm = 0xD0
+	cotpData              = 0xF0
+)
+
+// S7 PDU types (ROSCTR)
+const (
+	rosctrJob      = 0x01
+	rosctrAckData  = 0x03
+	rosctrUserData = 0x07
+)
+
+// S7 job functions
+const (
+	functionSetup       = 0xF0
+	functionRead        = 0x04
+	functionWrite       = 0x05
+	functionStartUpload = 0x1D
+	functionUpload      = 0x1E
+	functionEndUpload   = 0x1F
+)
+
+// Transport sizes of the request items
+const (
+	transportBit     = 0x01
+	transportByte    = 0x02
+	transportChar    = 0x03
+	transportWord    = 0x04
+	transportInt     = 0x05
+	transportDWord   = 0x06
+	transportDInt    = 0x07
+	transportReal    = 0x08
+	transportCounter = 0x1C
+	transportTimer   = 0x1D
+)
+
+// Transport sizes of the response data
+const (
+	dataBit   = 0x03
+	dataByte  = 0x04 // length in bits
+	dataReal  = 0x07 // length in bytes
+	dataOctet = 0x09 // length in bytes
+)
+
+// Function groups and sub-functions of the user data requests
+const (
+	groupBlock      = 0x03
+	groupSZL        = 0x04
+	groupSecurity   = 0x05
+	subListType     = 0x02
+	subBlockInfo    = 0x03
+	subReadSZL      = 0x01
+	subSetPassword  = 0x01
+	blockInfoLength = 78
+)
+
+// Error code of a block list of a type without blocks
+const errNoBlockAvailable = 0xD20E
+
+// Bytes of the S7 header, parameters and data headers of a SZL or block list response
+const userDataOverhead = 10 + 12 + 4
+
+// session is the protocol state of a client connection
+type session struct {
+	server    *Server
+	pduLength int
+	pending   []byte // remainder of a SZL or block list answer
+	sequence  byte
+	uploads   map[byte][]byte // remainder of the blocks being uploaded, by upload ID
+	uploadID  byte
+	password  string // session password of the client
+}
+
+// item is a read or write variable of a request
+type item struct {
+	transport int
+	count     int
+	db        int
+	area      int
+	address   int // bit address, or element index of counters and timers
+}
+
+// handle answers a TPKT telegram, drop is true when the connection should be closed
+func (c *session) handle(request []byte) (response []byte, drop bool) {
+	if len(request) < 7 {
+		return nil, true
+	}
+	switch request[5] {
+	case cotpConnectionRequest:
+		return c.connectionConfirm(request), false
+	case cotpData:
+	default:
+		return nil, false
+	}
+	if len(request) < 17 || request[7] != 0x32 {
+		return nil, true
+	}
+
+	s := c.server
+	s.mu.Lock()
+	s.requests++
+	delay := s.delay
+	if s.dropNext > 0 {
+		s.dropNext--
+		s.mu.Unlock()
+		return nil, true
+	}
+	s.mu.Unlock()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	rosctr := request[8]
+	pduRef := request[11:13]
+	var headerSize int
+	switch rosctr {
+	case rosctrJob:
+		headerSize = 10
+	case rosctrUserData:
+		headerSize = 10
+	default:
+		return nil, false
+	}
+	parLength := int(binary.BigEndian.Uint16(request[13:]))
+	dataLength := int(binary.BigEndian.Uint16(request[15:]))
+	paramStart := 7 + headerSize
+	if len(request) < paramStart+parLength+dataLength || parLength == 0 {
+		return nil, true
+	}
+	params := request[paramStart : paramStart+parLength]
+	data := request[paramStart+parLength : paramStart+parLength+dataLength]
+
+	if rosctr == rosctrUserData {
+		return c.userData(pduRef, params, data), false
+	}
+	s.mu.Lock()
+	code, failed := s.functionErrors[params[0]]
+	s.mu.Unlock()
+	if failed {
+		return ackData(pduRef, byte(code>>8), byte(code), nil, nil), false
+	}
+	switch params[0] {
+	case functionSetup:
+		return c.setupCommunication(pduRef, params), false
+	case functionRead:
+		return c.readVar(pduRef, params), false
+	case functionWrite:
+		return c.writeVar(pduRef, params, data), false
+	case functionStartUpload:
+		return c.startUpload(pduRef, params), false
+	case functionUpload:
+		return c.upload(pduRef, params), false
+	case functionEndUpload:
+		return c.endUpload(pduRef, params), false
+	default:
+		// function not supported
+		return ackData(pduRef, 0x81, 0x04, nil, nil), false
+	}
+}
+
+// connectionConfirm answers the COTP connection request and records its TSAPs
+func (c *session) connectionConfirm(request []byte) []byte {
+	var local, remote uint16
+	for offset := 11; offset+2 <= len(request); {
+		code := request[offset]
+		length := int(request[offset+1])
+		if offset+2+length > len(request) {
+			break
+		}
+		value := request[offset+2 : offset+2+length]
+		if length == 2 {
+			switch code {
+			case 0xC1:
+				local = binary.BigEndian.Uint16(value)
+			case 0xC2:
+				remote = binary.BigEndian.Uint16(value)
+			}
+		}
+		offset += 2 + length
+	}
+	c.server.mu.Lock()
+	c.server.localTSAP = local
+	c.server.remoteTSAP = remote
+	c.server.mu.Unlock()
+
+	response := make([]byte, len(request))
+	copy(response, request)
+	response[5] = cotpConnectionConfirm
+	// destination reference is the source reference of the request
+	response[6], response[7] = request[8], request[9]
+	response[8], response[9] = 0x00, 0x01
+	return response
+}
+
+// setupCommunication negotiates the PDU length
+func (c *session) setupCommunication(pduRef []byte, params []byte) []byte {
+	c.pduLength = maxPduLength
+	if len(params) >= 8 {
+		if requested := int(binary.BigEndian.Uint16(params[6:])); requested > 0 && requested < c.pduLength {
+			c.pduLength = requested
+		}
+	}
+	response := []byte{functionSetup, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00}
+	binary.BigEndian.PutUint16(response[6:], uint16(c.pduLength))
+	return ackData(pduRef, 0, 0, response, nil)
+}
+
+// parseItems parses the variable specifications of a read or write request
+func parseItems(params []byte) ([]item, bool) {
+	if len(params) < 2 {
+		return nil, false
+	}
+	count := int(params[1])
+	if len(params) < 2+count*12 {
+		return nil, false
+	}
+	items := make([]item, count)
+	for i := range items {
+		spec := params[2+i*12 : 2+(i+1)*12]
+		if spec[0] != 0x12 || spec[1] != 0x0A || spec[2] != 0x10 {
+			return nil, false
+		}
+		items[i] = item{
+			transport: int(spec[3]),
+			count:     int(binary.BigEndian.Uint16(spec[4:])),
+			db:        int(binary.BigEndian.Uint16(spec[6:])),
+			area:      int(spec[8]),
+			address:   int(spec[9])<<16 | int(spec[10])<<8 | int(spec[11]),
+		}
+	}
+	return items, true
+}
+
+// elementSize returns the bytes of an element of the transport size
+func elementSize(transport int) int {
+	switch transport {
+	case transportBit, transportByte, transportChar:
+		return 1
+	case transportWord, transportInt, transportCounter, transportTimer:
+		return 2
+	case transportDWord, transportDInt, transportReal:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// location returns the byte offset and size of the item
+func (i item) location() (start int, size int) {
+	switch i.transport {
+	case transportBit:
+		return i.address >> 3, 1
+	case transportCounter, transportTimer:
+		return i.address * 2, i.count * 2
+	default:
+		return i.address >> 3, i.count * elementSize(i.transport)
+	}
+}
+
+// itemError returns the access error of a client without the session password of a protected server,
+// or the error of the item, the caller must hold the mutex
+func (c *session) itemError(i item) byte {
+	if c.server.password != "" && c.password != c.server.password {
+		return ReturnAccessDenied
+	}
+	return c.server.itemError(i)
+}
+
+// itemError returns the injected or address error of the item, the caller must hold the mutex
+func (s *Server) itemError(i item) byte {
+	start, size := i.location()
+	db := i.db
+	if i.area != AreaDB {
+		db = 0
+	}
+	for address, code := range s.itemErrors {
+		if address.Area == i.area && address.DB == db && address.Start >= start && address.Start < start+max(size, 1) {
+			return code
+		}
+	}
+	if elementSize(i.transport) == 0 {
+		return ReturnTypeNotSupport
+	}
+	if _, err := s.area(i.area, i.db, start, size); err != nil {
+		if err == ErrAreaNotFound {
+			return ReturnObjectNotExist
+		}
+		return ReturnAddressOutRange
+	}
+	return ReturnSuccess
+}
+
+// readVar answers a read var request
+func (c *session) readVar(pduRef []byte, params []byte) []byte {
+	items, ok := parseItems(params)
+	if !ok {
+		return ackData(pduRef, 0x85, 0x00, nil, nil)
+	}
+
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var data []byte
+	for n, i := range items {
+		if code := c.itemError(i); code != ReturnSuccess {
+			data = append(data, code, 0x00, 0x00, 0x00)
+			continue
+		}
+		start, size := i.location()
+		buffer, _ := s.area(i.area, i.db, start, size)
+
+		var value []byte
+		header := []byte{ReturnSuccess, 0, 0, 0}
+		switch i.transport {
+		case transportBit:
+			value = []byte{(buffer[0] >> uint(i.address&0x07)) & 0x01}
+			header[1] = dataBit
+			binary.BigEndian.PutUint16(header[2:], 1)
+		case transportReal:
+			value = buffer
+			header[1] = dataReal
+			binary.BigEndian.PutUint16(header[2:], uint16(size))
+		case transportCounter, transportTimer:
+			value = buffer
+			header[1] = dataOctet
+			binary.BigEndian.PutUint16(header[2:], uint16(size))
+		default:
+			value = buffer
+			header[1] = dataByte
+			binary.BigEndian.PutUint16(header[2:], uint16(size*8))
+		}
+		data = append(data, header...)
+		data = append(data, value...)
+		// odd sized data is padded except for the last item
+		if len(value)%2 != 0 && n < len(items)-1 {
+			data = append(data, 0x00)
+		}
+	}
+	return ackData(pduRef, 0, 0, []byte{functionRead, byte(len(items))}, data)
+}
+
+// writeVar answers a write var request
+func (c *session) writeVar(pduRef []byte, params []byte, data []byte) []byte {
+	items, ok := parseItems(params)
+	if !ok {
+		return ackData(pduRef, 0x85, 0x00, nil, nil)
+	}
+
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	codes := make([]byte, len(items))
+	offset := 0
+	for n, i := range items {
+		if offset+4 > len(data) {
+			codes[n] = ReturnInconsistent
+			continue
+		}
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+		switch data[offset+1] {
+		case dataBit, dataReal, dataOctet:
+		default:
+			length = length / 8
+		}
+		if offset+4+length > len(data) {
+			codes[n] = ReturnInconsistent
+			offset = len(data)
+			continue
+		}
+		value := data[offset+4 : offset+4+length]
+		offset += 4 + length
+		if length%2 != 0 {
+			offset++
+		}
+
+		if code := c.itemError(i); code != ReturnSuccess {
+			codes[n] = code
+			continue
+		}
+		start, size := i.location()
+		buffer, _ := s.area(i.area, i.db, start, size)
+		if i.transport == transportBit {
+			if len(value) < 1 {
+				codes[n] = ReturnInconsistent
+				continue
+			}
+			mask := byte(1) << uint(i.address&0x07)
+			if value[0]&0x01 != 0 {
+				buffer[0] |= mask
+			} else {
+				buffer[0] &^= mask
+			}
+		} else {
+			if len(value) != size {
+				codes[n] = ReturnInconsistent
+				continue
+			}
+			copy(buffer, value)
+		}
+		codes[n] = ReturnSuccess
+	}
+	return ackData(pduRef, 0, 0, []byte{functionWrite, byte(len(items))}, codes)
+}
+
+// startUpload opens an upload session of the block of the file name, "_0" + block type + 5 digits block number + "A"
+func (c *session) startUpload(pduRef []byte, params []byte) []byte {
+	if len(params) < 18 || params[8] != 9 {
+		return ackData(pduRef, 0xD2, 0x01, nil, nil)
+	}
+	blockType := int(params[11])
+	number, err := strconv.Atoi(string(params[12:17]))
+
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	block, ok := s.blocks[blockKey{blockType: blockType, number: number}]
+	if err != nil || !ok {
+		return ackData(pduRef, 0xD2, 0x09, nil, nil)
+	}
+	if c.uploads == nil {
+		c.uploads = make(map[byte][]byte)
+	}
+	c.uploadID++
+	c.uploads[c.uploadID] = block.Data
+	s.openUploads++
+
+	// the upload ID, then the length of the block as 7 ASCII digits
+	response := []byte{functionStartUpload, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, c.uploadID, 0x07}
+	response = append(response, fmt.Sprintf("%07d", len(block.Data))...)
+	return ackData(pduRef, 0, 0, response, nil)
+}
+
+// upload answers the next part of the block of the upload session, the status is 0x01 while more data follows
+func (c *session) upload(pduRef []byte, params []byte) []byte {
+	if len(params) < 8 {
+		return ackData(pduRef, 0xD2, 0x01, nil, nil)
+	}
+	pending, ok := c.uploads[params[7]]
+	if !ok {
+		return ackData(pduRef, 0xD2, 0x09, nil, nil)
+	}
+	pduLength := c.pduLength
+	if pduLength == 0 {
+		pduLength = maxPduLength
+	}
+	// S7 header, parameters and data header
+	maxPayload := pduLength - 12 - 2 - 4
+	status := byte(0x00)
+	if len(pending) > maxPayload {
+		status = 0x01
+		c.uploads[params[7]] = pending[maxPayload:]
+		pending = pending[:maxPayload]
+	} else {
+		c.uploads[params[7]] = nil
+	}
+	data := []byte{0, 0, 0x00, 0xFB}
+	binary.BigEndian.PutUint16(data[0:], uint16(len(pending)))
+	return ackData(pduRef, 0, 0, []byte{functionUpload, status}, append(data, pending...))
+}
+
+// endUpload closes the upload session
+func (c *session) endUpload(pduRef []byte, params []byte) []byte {
+	if len(params) < 8 {
+		return ackData(pduRef, 0xD2, 0x01, nil, nil)
+	}
+	if _, ok := c.uploads[params[7]]; !ok {
+		return ackData(pduRef, 0xD2, 0x09, nil, nil)
+	}
+	delete(c.uploads, params[7])
+	c.server.mu.Lock()
+	c.server.openUploads--
+	c.server.mu.Unlock()
+	return ackData(pduRef, 0, 0, []byte{functionEndUpload}, nil)
+}
+
+// setPassword decodes the session password of the client, a wrong password is refused
+func (c *session) setPassword(pduRef []byte, params []byte, data []byte) []byte {
+	// return code, transport size, length and the 8 encoded characters
+	if len(data) < 12 {
+		return userDataResponse(pduRef, params, 0, true, 0xD401, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+	encoded := data[4:12]
+	password := make([]byte, 8)
+	for i := range password {
+		password[i] = encoded[i] ^ 0x55
+		if i >= 2 {
+			password[i] ^= encoded[i-2]
+		}
+	}
+
+	c.server.mu.Lock()
+	valid := string(bytes.TrimRight(password, " ")) == c.server.password
+	if valid {
+		c.password = c.server.password
+	}
+	c.server.mu.Unlock()
+	if !valid {
+		return userDataResponse(pduRef, params, 0, true, 0xD602, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+	return userDataResponse(pduRef, params, 0, true, 0, []byte{ReturnSuccess, 0x09, 0, 0})
+}
+
+// userData answers the SZL read, block list, block info and session password requests, the other user data functions are not supported
+func (c *session) userData(pduRef []byte, params []byte, data []byte) []byte {
+	// head (3), parameter length, method, type/function group, sub-function, sequence
+	if len(params) < 8 {
+		return userDataResponse(pduRef, params, 0, true, 0x8104, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+	group, subFunction := params[5]&0x0F, params[6]
+	switch {
+	case group == groupSZL && subFunction == subReadSZL:
+	case group == groupBlock && (subFunction == subListType || subFunction == subBlockInfo):
+	case group == groupSecurity && subFunction == subSetPassword:
+		return c.setPassword(pduRef, params, data)
+	default:
+		return userDataResponse(pduRef, params, 0, true, 0x8104, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+
+	if params[3] == 0x08 {
+		// next fragment of a SZL or block list answer
+		if c.pending == nil {
+			return userDataResponse(pduRef, params, 0, true, 0xD402, []byte{ReturnObjectNotExist, 0, 0, 0})
+		}
+		return c.fragment(pduRef, params, c.pending)
+	}
+
+	switch {
+	case group == groupBlock && subFunction == subListType:
+		return c.listBlocks(pduRef, params, data)
+	case group == groupBlock:
+		return c.blockInfo(pduRef, params, data)
+	}
+
+	if len(data) < 8 {
+		return userDataResponse(pduRef, params, 0, true, 0xD401, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+	id := int(binary.BigEndian.Uint16(data[4:]))
+	index := int(binary.BigEndian.Uint16(data[6:]))
+
+	c.server.mu.Lock()
+	list, ok := c.server.szl[szlKey{id: id, index: index}]
+	c.server.mu.Unlock()
+	if !ok {
+		return userDataResponse(pduRef, params, 0, true, 0xD401, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+
+	payload := make([]byte, 8, 8+len(list.data))
+	binary.BigEndian.PutUint16(payload[0:], uint16(id))
+	binary.BigEndian.PutUint16(payload[2:], uint16(index))
+	binary.BigEndian.PutUint16(payload[4:], uint16(list.recordSize))
+	if list.recordSize > 0 {
+		binary.BigEndian.PutUint16(payload[6:], uint16(len(list.data)/list.recordSize))
+	}
+	payload = append(payload, list.data...)
+	c.sequence++
+	return c.fragment(pduRef, params, payload)
+}
+
+// listBlocks answers the numbers of the blocks of the requested type, 4 bytes each
+func (c *session) listBlocks(pduRef []byte, params []byte, data []byte) []byte {
+	// return code, transport size, length, '0' and the block type
+	if len(data) < 6 {
+		return userDataResponse(pduRef, params, 0, true, 0xD401, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+	blockType := int(data[5])
+
+	s := c.server
+	s.mu.Lock()
+	code := s.blockListErrors[blockType]
+	var numbers []int
+	for key := range s.blocks {
+		if key.blockType == blockType {
+			numbers = append(numbers, key.number)
+		}
+	}
+	s.mu.Unlock()
+	if code != 0 {
+		return userDataResponse(pduRef, params, 0, true, code, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+	// like the PLCs, a type without blocks is answered by an error
+	if len(numbers) == 0 {
+		return userDataResponse(pduRef, params, 0, true, errNoBlockAvailable, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+
+	sort.Ints(numbers)
+	payload := make([]byte, 0, 4*len(numbers))
+	for _, number := range numbers {
+		// block number, flags and language
+		payload = append(payload, byte(number>>8), byte(number), 0x22, 0x05)
+	}
+	c.sequence++
+	return c.fragment(pduRef, params, payload)
+}
+
+// blockInfo answers the header of the requested block
+func (c *session) blockInfo(pduRef []byte, params []byte, data []byte) []byte {
+	// return code, transport size, length, '0', the block type, the ASCII block number and 'A'
+	if len(data) < 11 {
+		return userDataResponse(pduRef, params, 0, true, 0xD401, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+	blockType := int(data[5])
+	number, err := strconv.Atoi(string(data[6:11]))
+
+	c.server.mu.Lock()
+	block, ok := c.server.blocks[blockKey{blockType: blockType, number: number}]
+	c.server.mu.Unlock()
+	if err != nil || !ok {
+		return userDataResponse(pduRef, params, 0, true, 0xD209, []byte{ReturnObjectNotExist, 0, 0, 0})
+	}
+
+	// the timestamps are the days since 1984
+	days := uint16(max(block.CodeDate.Sub(time.Date(1984, 1, 1, 0, 0, 0, 0, time.UTC)).Hours()/24, 0))
+	info := make([]byte, blockInfoLength)
+	info[9] = 0x01  // flags
+	info[10] = 0x05 // language
+	info[11] = byte(blockType)
+	binary.BigEndian.PutUint16(info[12:], uint16(number))
+	binary.BigEndian.PutUint32(info[14:], uint32(len(block.Data)+36))
+	binary.BigEndian.PutUint16(info[26:], days)
+	binary.BigEndian.PutUint16(info[32:], days)
+	binary.BigEndian.PutUint16(info[40:], uint16(len(block.Data)))
+	binary.BigEndian.PutUint16(info[68:], block.CheckSum)
+
+	response := []byte{ReturnSuccess, 0x09, 0, 0}
+	binary.BigEndian.PutUint16(response[2:], uint16(len(info)))
+	return userDataResponse(pduRef, params, 0, true, 0, append(response, info...))
+}
+
+// fragment answers the payload, the remainder is kept for the next requests
+func (c *session) fragment(pduRef []byte, params []byte, payload []byte) []byte {
+	pduLength := c.pduLength
+	if pduLength == 0 {
+		pduLength = maxPduLength
+	}
+	maxPayload := pduLength - userDataOverhead
+	last := true
+	if len(payload) > maxPayload {
+		c.pending = payload[maxPayload:]
+		payload = payload[:maxPayload]
+		last = false
+	} else {
+		c.pending = nil
+	}
+	data := []byte{ReturnSuccess, 0x09, 0, 0}
+	binary.BigEndian.PutUint16(data[2:], uint16(len(payload)))
+	data = append(data, payload...)
+	return userDataResponse(pduRef, params, c.sequence, last, 0, data)
+}
+
+// userDataResponse builds the user data answer of the request parameters
+func userDataResponse(pduRef []byte, params []byte, sequence byte, last bool, errorCode uint16, data []byte) []byte {
+	group := byte(0x84)
+	subFunction := byte(0x01)
+	if len(params) >= 7 {
+		group = 0x80 | params[5]&0x0F
+		subFunction = params[6]
+	}
+	lastUnit := byte(0x01)
+	if last {
+		lastUnit = 0x00
+	}
+	response := []byte{0x00, 0x01, 0x12, 0x08, 0x12, group, subFunction, sequence, 0x00, lastUnit, 0, 0}
+	binary.BigEndian.PutUint16(response[10:], errorCode)
+	return telegram([]byte{0x32, rosctrUserData, 0, 0, pduRef[0], pduRef[1], 0, 0, 0, 0}, response, data)
+}
+
+// ackData builds an acknowledgement of a job
+func ackData(pduRef []byte, errorClass byte, errorCode byte, params []byte, data []byte) []byte {
+	return telegram([]byte{0x32, rosctrAckData, 0, 0, pduRef[0], pduRef[1], 0, 0, 0, 0, errorClass, errorCode}, params, data)
+}
+
+// telegram wraps the S7 header, parameters and data into TPKT and COTP
+func telegram(header []byte, params []byte, data []byte) []byte {
+	binary.BigEndian.PutUint16(header[6:], uint16(len(params)))
+	binary.BigEndian.PutUint16(header[8:], uint16(len(data)))
+	response := []byte{3, 0, 0, 0, 2, cotpData, 0x80}
+	response = append(response, header...)
+	response = append(response, params...)
+	response = append(response, data...)
+	binary.BigEndian.PutUint16(response[2:], uint16(len(response)))
+	return response
+}
@@ -0,0 +1,149 @@
+package s7server
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// The expected answers below are written out byte by byte in the layout of the answers of the S7-300/400 CPUs
+// (TPKT, COTP, S7 header, parameters and data), they are not built by the helpers of protocol.go. An encoding
+// error shared by the simulator and the driver fails here, not only in the e2e tests.
+
+// telegramBytes decodes a telegram written in hex bytes separated by spaces
+func telegramBytes(t *testing.T, telegram string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(strings.Join(strings.Fields(telegram), ""))
+	if err != nil {
+		t.Fatalf("invalid telegram %q, error: %v", telegram, err)
+	}
+	return data
+}
+
+// exchange sends the request telegrams to a session in order and compares the answers
+func exchange(t *testing.T, c *session, telegrams [][2]string) {
+	t.Helper()
+	for _, telegram := range telegrams {
+		response, drop := c.handle(telegramBytes(t, telegram[0]))
+		if drop {
+			t.Fatalf("request %s dropped the connection", telegram[0])
+		}
+		if want := telegramBytes(t, telegram[1]); !bytes.Equal(response, want) {
+			t.Errorf("answer of %s\n got: % X\nwant: % X", telegram[0], response, want)
+		}
+	}
+}
+
+func TestConnectionConfirm(t *testing.T) {
+	s := NewServer()
+	exchange(t, &session{server: s}, [][2]string{{
+		// connection request of rack 0, slot 2
+		"03 00 00 16 11 E0 00 00 00 01 00 C0 01 0A C1 02 01 00 C2 02 01 02",
+		"03 00 00 16 11 D0 00 01 00 01 00 C0 01 0A C1 02 01 00 C2 02 01 02",
+	}, {
+		// setup communication, 480 bytes PDU
+		"03 00 00 19 02 F0 80 32 01 00 00 04 00 00 08 00 00 F0 00 00 01 00 01 01 E0",
+		"03 00 00 1B 02 F0 80 32 03 00 00 04 00 00 08 00 00 00 00 F0 00 00 01 00 01 01 E0",
+	}})
+	if local, remote := s.TSAPs(); local != 0x0100 || remote != 0x0102 {
+		t.Errorf("TSAPs() = %04X, %04X", local, remote)
+	}
+}
+
+func TestReadSZL(t *testing.T) {
+	s := NewServer()
+	// SZL 0x0011 module identification, a record of the MLFB of a CPU 315-2 PN/DP
+	s.SetSZL(0x0011, 0x0000, 28, telegramBytes(t, "00 01 36 45 53 37 20 33 31 35 2D 32 45 48 31 34 2D 30 41 42 30 20 00 C0 00 04 00 01"))
+	exchange(t, &session{server: s}, [][2]string{{
+		"03 00 00 21 02 F0 80 32 07 00 00 05 00 00 08 00 08 00 01 12 04 11 44 01 00 FF 09 00 04 00 11 00 00",
+		"03 00 00 45 02 F0 80 32 07 00 00 05 00 00 0C 00 28 00 01 12 08 12 84 01 01 00 00 00 00 FF 09 00 24 " +
+			"00 11 00 00 00 1C 00 01 00 01 36 45 53 37 20 33 31 35 2D 32 45 48 31 34 2D 30 41 42 30 20 00 C0 00 04 00 01",
+	}, {
+		// a partial list the CPU doesn't have
+		"03 00 00 21 02 F0 80 32 07 00 00 06 00 00 08 00 08 00 01 12 04 11 44 01 00 FF 09 00 04 0F 99 00 00",
+		"03 00 00 21 02 F0 80 32 07 00 00 06 00 00 0C 00 04 00 01 12 08 12 84 01 00 00 00 D4 01 0A 00 00 00",
+	}})
+}
+
+func TestListBlocks(t *testing.T) {
+	s := NewServer()
+	s.SetBlock(BlockDB, 10, Block{Data: []byte{0x00}})
+	s.SetBlock(BlockDB, 1, Block{Data: []byte{0x00}})
+	exchange(t, &session{server: s}, [][2]string{{
+		// the DBs, block number, flags and language of each
+		"03 00 00 1F 02 F0 80 32 07 00 00 05 00 00 08 00 06 00 01 12 04 11 43 02 00 FF 09 00 02 30 41",
+		"03 00 00 29 02 F0 80 32 07 00 00 05 00 00 0C 00 0C 00 01 12 08 12 83 02 01 00 00 00 00 FF 09 00 08 00 01 22 05 00 0A 22 05",
+	}, {
+		// a type without blocks is answered by the error 0xD20E, not by an empty list
+		"03 00 00 1F 02 F0 80 32 07 00 00 06 00 00 08 00 06 00 01 12 04 11 43 02 00 FF 09 00 02 30 45",
+		"03 00 00 21 02 F0 80 32 07 00 00 06 00 00 0C 00 04 00 01 12 08 12 83 02 00 00 00 D2 0E 0A 00 00 00",
+	}})
+}
+
+func TestListBlocksFragments(t *testing.T) {
+	s := NewServer()
+	for number := 1; number <= 60; number++ {
+		s.SetBlock(BlockFC, number, Block{Data: []byte{0x00}})
+	}
+	c := &session{server: s}
+	exchange(t, c, [][2]string{{
+		// 240 bytes PDU, 214 bytes of the list per answer
+		"03 00 00 19 02 F0 80 32 01 00 00 04 00 00 08 00 00 F0 00 00 01 00 01 00 F0",
+		"03 00 00 1B 02 F0 80 32 03 00 00 04 00 00 08 00 00 00 00 F0 00 00 01 00 01 00 F0",
+	}})
+
+	response, _ := c.handle(telegramBytes(t, "03 00 00 1F 02 F0 80 32 07 00 00 05 00 00 08 00 06 00 01 12 04 11 43 02 00 FF 09 00 02 30 43"))
+	// more data units follow, with the sequence number of the next request
+	if want := telegramBytes(t, "03 00 00 F7 02 F0 80 32 07 00 00 05 00 00 0C 00 DA 00 01 12 08 12 83 02 01 00 01 00 00 FF 09 00 D6"); !bytes.HasPrefix(response, want) {
+		t.Errorf("first answer = % X, want the prefix % X", response[:min(len(response), len(want))], want)
+	}
+	response, _ = c.handle(telegramBytes(t, "03 00 00 21 02 F0 80 32 07 00 00 06 00 00 0C 00 04 00 01 12 08 12 43 02 01 00 00 00 00 0A 00 00 00"))
+	if want := telegramBytes(t, "03 00 00 3B 02 F0 80 32 07 00 00 06 00 00 0C 00 1E 00 01 12 08 12 83 02 01 00 00 00 00 FF 09 00 1A 22 05 00 37"); !bytes.HasPrefix(response, want) {
+		t.Errorf("last answer = % X, want the prefix % X", response[:min(len(response), len(want))], want)
+	}
+}
+
+func TestUploadBlock(t *testing.T) {
+	s := NewServer()
+	s.SetBlock(BlockOB, 1, Block{Data: []byte{0x01, 0x02, 0x03, 0x04}})
+	exchange(t, &session{server: s}, [][2]string{{
+		// start upload of _0800001A, the upload ID and the 7 ASCII digits of the length are answered
+		"03 00 00 23 02 F0 80 32 01 00 00 00 00 00 12 00 00 1D 00 00 00 00 00 00 00 09 5F 30 38 30 30 30 30 31 41",
+		"03 00 00 23 02 F0 80 32 03 00 00 00 00 00 10 00 00 00 00 1D 00 01 00 00 00 00 01 07 30 30 30 30 30 30 34",
+	}, {
+		// upload, the data length and 0x00FB precede the block
+		"03 00 00 19 02 F0 80 32 01 00 00 00 00 00 08 00 00 1E 00 00 00 00 00 00 01",
+		"03 00 00 1D 02 F0 80 32 03 00 00 00 00 00 02 00 08 00 00 1E 00 00 04 00 FB 01 02 03 04",
+	}, {
+		"03 00 00 19 02 F0 80 32 01 00 00 00 00 00 08 00 00 1F 00 00 00 00 00 00 01",
+		"03 00 00 14 02 F0 80 32 03 00 00 00 00 00 01 00 00 00 00 1F",
+	}, {
+		// a block the CPU doesn't have
+		"03 00 00 23 02 F0 80 32 01 00 00 00 00 00 12 00 00 1D 00 00 00 00 00 00 00 09 5F 30 38 30 30 30 30 32 41",
+		"03 00 00 13 02 F0 80 32 03 00 00 00 00 00 00 00 00 D2 09",
+	}})
+	if s.OpenUploads() != 0 {
+		t.Errorf("OpenUploads() = %d after the end of the upload", s.OpenUploads())
+	}
+}
+
+func TestReadMultiItemErrors(t *testing.T) {
+	s := NewServer()
+	s.SetDB(1, []byte{0xAA})
+	if err := s.WriteArea(AreaMK, 0, 10, []byte{0x12, 0x34}); err != nil {
+		t.Fatal(err)
+	}
+	exchange(t, &session{server: s}, [][2]string{{
+		// DB1.DBB0, DB9.DBW0 of a missing DB and MW10
+		"03 00 00 37 02 F0 80 32 01 00 00 00 07 00 26 00 00 04 03 " +
+			"12 0A 10 02 00 01 00 01 84 00 00 00 " +
+			"12 0A 10 04 00 01 00 09 84 00 00 00 " +
+			"12 0A 10 04 00 01 00 00 83 00 00 50",
+		// the odd byte is padded, the failed item has no data
+		"03 00 00 25 02 F0 80 32 03 00 00 00 07 00 02 00 10 00 00 04 03 " +
+			"FF 04 00 08 AA 00 " +
+			"0A 00 00 00 " +
+			"FF 04 00 10 12 34",
+	}})
+}
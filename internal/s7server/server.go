@@ -0,0 +1,441 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package s7server provides an in-process stand-in of a S7 PLC.
+// It speaks ISO-on-TCP (RFC1006/COTP) and the S7comm subset used by the
+// device service: setup communication, read var, write var, SZL read, the
+// block list and block info requests, the block upload, and the session password.
+// The memory areas are kept in memory, faults can be injected for tests.
+package s7server
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Area IDs
+const (
+	AreaPE = 0x81 // process inputs
+	AreaPA = 0x82 // process outputs
+	AreaMK = 0x83 // merkers
+	AreaDB = 0x84 // data blocks
+	AreaCT = 0x1C // counters
+	AreaTM = 0x1D // timers
+)
+
+// Item return codes
+const (
+	ReturnSuccess         = 0xFF
+	ReturnHardwareFault   = 0x01
+	ReturnAccessDenied    = 0x03
+	ReturnAddressOutRange = 0x05
+	ReturnTypeNotSupport  = 0x06
+	ReturnInconsistent    = 0x07
+	ReturnObjectNotExist  = 0x0A
+)
+
+// Block types
+const (
+	BlockOB = 0x38
+	BlockDB = 0x41
+	BlockFC = 0x43
+	BlockFB = 0x45
+)
+
+// PDU size negotiated with the clients
+const maxPduLength = 480
+
+// Size of the I/Q/M areas
+const processAreaSize = 1024
+
+// ErrAreaNotFound is returned when the area or DB does not exist
+var ErrAreaNotFound = errors.New("area not found")
+
+// ErrOutOfRange is returned when the address exceeds the area size
+var ErrOutOfRange = errors.New("address out of range")
+
+type areaKey struct {
+	area int
+	db   int
+}
+
+// ItemAddress identifies a read or write item for fault injection
+type ItemAddress struct {
+	Area  int
+	DB    int
+	Start int // byte offset, the items covering it fail
+}
+
+type szlKey struct {
+	id    int
+	index int
+}
+
+type szlList struct {
+	recordSize int
+	data       []byte
+}
+
+type blockKey struct {
+	blockType int
+	number    int
+}
+
+// Block is a program or data block of the PLC
+type Block struct {
+	Data     []byte // MC7 code, or the data of a DB
+	CheckSum uint16
+	CodeDate time.Time
+}
+
+// Server is a S7 PLC stand-in serving in-memory areas
+type Server struct {
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	areas    map[areaKey][]byte
+	szl      map[szlKey]szlList
+	blocks   map[blockKey]Block
+	wg       sync.WaitGroup
+
+	// injected faults
+	delay           time.Duration
+	dropNext        int
+	itemErrors      map[ItemAddress]byte
+	blockListErrors map[int]uint16
+	functionErrors  map[byte]uint16
+
+	// upload sessions started and not ended
+	openUploads int
+	// session password protecting the read and write of the areas
+	password string
+
+	// TSAPs of the last connection request
+	localTSAP  uint16
+	remoteTSAP uint16
+	requests   int
+}
+
+// NewServer creates a server with empty I/Q/M areas and no DBs
+func NewServer() *Server {
+	s := &Server{
+		conns:           make(map[net.Conn]struct{}),
+		areas:           make(map[areaKey][]byte),
+		szl:             make(map[szlKey]szlList),
+		blocks:          make(map[blockKey]Block),
+		itemErrors:      make(map[ItemAddress]byte),
+		blockListErrors: make(map[int]uint16),
+		functionErrors:  make(map[byte]uint16),
+	}
+	for _, area := range []int{AreaPE, AreaPA, AreaMK} {
+		s.areas[areaKey{area: area}] = make([]byte, processAreaSize)
+	}
+	return s
+}
+
+// Start listens on the address, like "127.0.0.1:0", and serves the clients in background
+func (s *Server) Start(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns[conn] = struct{}{}
+			s.mu.Unlock()
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Addr returns the listening address
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Stop closes the listener and all the connections
+func (s *Server) Stop() {
+	s.mu.Lock()
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// SetDB creates or replaces a data block
+func (s *Server) SetDB(number int, data []byte) {
+	s.SetArea(AreaDB, number, data)
+}
+
+// SetArea creates or replaces an area, the DB number is only used by AreaDB
+func (s *Server) SetArea(area int, db int, data []byte) {
+	if area != AreaDB {
+		db = 0
+	}
+	buffer := make([]byte, len(data))
+	copy(buffer, data)
+	s.mu.Lock()
+	s.areas[areaKey{area: area, db: db}] = buffer
+	s.mu.Unlock()
+}
+
+// ReadArea returns a copy of the area bytes
+func (s *Server) ReadArea(area int, db int, start int, size int) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buffer, err := s.area(area, db, start, size)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, size)
+	copy(data, buffer)
+	return data, nil
+}
+
+// WriteArea writes the bytes into the area
+func (s *Server) WriteArea(area int, db int, start int, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buffer, err := s.area(area, db, start, len(data))
+	if err != nil {
+		return err
+	}
+	copy(buffer, data)
+	return nil
+}
+
+// WriteBit sets or clears a bit of the area
+func (s *Server) WriteBit(area int, db int, start int, bit int, value bool) error {
+	if bit < 0 || bit > 7 {
+		return ErrOutOfRange
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buffer, err := s.area(area, db, start, 1)
+	if err != nil {
+		return err
+	}
+	if value {
+		buffer[0] |= 1 << uint(bit)
+	} else {
+		buffer[0] &^= 1 << uint(bit)
+	}
+	return nil
+}
+
+// area returns the slice of the area, the caller must hold the mutex
+func (s *Server) area(area int, db int, start int, size int) ([]byte, error) {
+	if area != AreaDB {
+		db = 0
+	}
+	buffer, ok := s.areas[areaKey{area: area, db: db}]
+	if !ok {
+		return nil, ErrAreaNotFound
+	}
+	if start < 0 || size < 0 || start+size > len(buffer) {
+		return nil, ErrOutOfRange
+	}
+	return buffer[start : start+size], nil
+}
+
+// SetSZL sets the records of a SZL partial list
+func (s *Server) SetSZL(id int, index int, recordSize int, records []byte) {
+	data := make([]byte, len(records))
+	copy(data, records)
+	s.mu.Lock()
+	s.szl[szlKey{id: id, index: index}] = szlList{recordSize: recordSize, data: data}
+	s.mu.Unlock()
+}
+
+// SetBlock creates or replaces a block
+func (s *Server) SetBlock(blockType int, number int, block Block) {
+	block.Data = append([]byte(nil), block.Data...)
+	s.mu.Lock()
+	s.blocks[blockKey{blockType: blockType, number: number}] = block
+	s.mu.Unlock()
+}
+
+// DeleteBlock removes a block
+func (s *Server) DeleteBlock(blockType int, number int) {
+	s.mu.Lock()
+	delete(s.blocks, blockKey{blockType: blockType, number: number})
+	s.mu.Unlock()
+}
+
+// SetPassword protects the read and write of the areas by a session password, empty removes the protection
+func (s *Server) SetPassword(password string) {
+	s.mu.Lock()
+	s.password = password
+	s.mu.Unlock()
+}
+
+// SetDelay delays every response, zero disables the delay
+func (s *Server) SetDelay(delay time.Duration) {
+	s.mu.Lock()
+	s.delay = delay
+	s.mu.Unlock()
+}
+
+// DropNextRequests closes the connection instead of answering the next n S7 requests
+func (s *Server) DropNextRequests(n int) {
+	s.mu.Lock()
+	s.dropNext = n
+	s.mu.Unlock()
+}
+
+// DropConnections closes all the client connections
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		_ = conn.Close()
+		delete(s.conns, conn)
+	}
+}
+
+// SetItemError answers the read and write items covering the address with the return code
+func (s *Server) SetItemError(address ItemAddress, code byte) {
+	if address.Area != AreaDB {
+		address.DB = 0
+	}
+	s.mu.Lock()
+	s.itemErrors[address] = code
+	s.mu.Unlock()
+}
+
+// SetBlockListError answers the block list requests of the block type with the error code
+func (s *Server) SetBlockListError(blockType int, code uint16) {
+	s.mu.Lock()
+	s.blockListErrors[blockType] = code
+	s.mu.Unlock()
+}
+
+// SetFunctionError answers the jobs of the function, like 0x1E upload, with the error class and code
+func (s *Server) SetFunctionError(function byte, errorClass byte, errorCode byte) {
+	s.mu.Lock()
+	s.functionErrors[function] = uint16(errorClass)<<8 | uint16(errorCode)
+	s.mu.Unlock()
+}
+
+// ClearFaults removes all the injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	s.delay = 0
+	s.dropNext = 0
+	s.itemErrors = make(map[ItemAddress]byte)
+	s.blockListErrors = make(map[int]uint16)
+	s.functionErrors = make(map[byte]uint16)
+	s.mu.Unlock()
+}
+
+// TSAPs returns the local (client) and remote (PLC) TSAPs of the last connection request
+func (s *Server) TSAPs() (local uint16, remote uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.localTSAP, s.remoteTSAP
+}
+
+// OpenUploads returns the number of upload sessions started and not ended, the sessions of
+// a closed connection are ended
+func (s *Server) OpenUploads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.openUploads
+}
+
+// Connections returns the number of open client connections
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Requests returns the number of S7 requests received
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// serve handles the telegrams of a connection until it is closed
+func (s *Server) serve(conn net.Conn) {
+	session := &session{server: s}
+	defer func() {
+		_ = conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.openUploads -= len(session.uploads)
+		s.mu.Unlock()
+	}()
+
+	for {
+		request, err := readTelegram(conn)
+		if err != nil {
+			return
+		}
+		response, drop := session.handle(request)
+		if drop {
+			return
+		}
+		if response == nil {
+			continue
+		}
+		if _, err = conn.Write(response); err != nil {
+			return
+		}
+	}
+}
+
+// readTelegram reads a TPKT telegram
+func readTelegram(conn net.Conn) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	if header[0] != 3 {
+		return nil, fmt.Errorf("invalid TPKT version %d", header[0])
+	}
+	length := int(binary.BigEndian.Uint16(header[2:]))
+	if length < 7 {
+		return nil, fmt.Errorf("invalid TPKT length %d", length)
+	}
+	telegram := make([]byte, length)
+	copy(telegram, header)
+	if _, err := io.ReadFull(conn, telegram[4:]); err != nil {
+		return nil, err
+	}
+	return telegram, nil
+}
@@ -0,0 +1,617 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package symbols reads the PLC variables and their absolute addresses from
+// STEP 7 and TIA Portal exports, like the DB sources and the symbol tables.
+package symbols
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxArrayElements limits the variables of an array
+const maxArrayElements = 65536
+
+// sizes in bytes of the elementary data types, BOOL is a bit
+var elementarySizes = map[string]int{
+	"BYTE": 1, "CHAR": 1, "SINT": 1, "USINT": 1,
+	"WORD": 2, "INT": 2, "UINT": 2, "DATE": 2, "S5TIME": 2, "WCHAR": 2,
+	"DWORD": 4, "DINT": 4, "UDINT": 4, "REAL": 4, "TIME": 4, "TIME_OF_DAY": 4, "TOD": 4,
+	"LWORD": 8, "LINT": 8, "ULINT": 8, "LREAL": 8, "LTIME": 8, "LTIME_OF_DAY": 8, "LTOD": 8,
+	"DATE_AND_TIME": 8, "DT": 8,
+	"DTL": 12,
+}
+
+// DataTypeSize returns the size in bytes of an elementary data type, 0 for BOOL
+func DataTypeSize(dataType string) (int, bool) {
+	dataType = strings.ToUpper(dataType)
+	if dataType == "BOOL" {
+		return 0, true
+	}
+	size, ok := elementarySizes[dataType]
+	return size, ok
+}
+
+// DataBlock is a non-optimized DB with the absolute offsets of its variables
+type DataBlock struct {
+	Name      string // the symbol of the DB, or DBn
+	Number    int    // 0 when the source names the DB by its symbol only
+	Size      int
+	Variables []Variable
+}
+
+// Variable is an elementary variable of a DB
+type Variable struct {
+	Name     string // the path in the DB, like Motor.Speed or Values[3]
+	DataType string // the S7 data type, like REAL or STRING[20]
+	Offset   int    // byte offset in the DB
+	Bit      int    // bit of BOOL variables
+	Size     int    // bytes, 0 for BOOL
+	ReadOnly bool
+	Comment  string
+}
+
+// Address returns the absolute address of the variable in S7 syntax, like DB4.DBX0.1 or DB4.DBW2
+func (v Variable) Address(dbNumber int) string {
+	switch {
+	case v.DataType == "BOOL":
+		return fmt.Sprintf("DB%d.DBX%d.%d", dbNumber, v.Offset, v.Bit)
+	case v.Size == 1:
+		return fmt.Sprintf("DB%d.DBB%d", dbNumber, v.Offset)
+	case v.Size == 2:
+		return fmt.Sprintf("DB%d.DBW%d", dbNumber, v.Offset)
+	default:
+		return fmt.Sprintf("DB%d.DBD%d", dbNumber, v.Offset)
+	}
+}
+
+// ParseDBSources parses the DB sources, the UDTs of a source can be used by the DBs of all the sources.
+// The variables are laid out by the S7 memory rules of the non-optimized DBs: BOOLs are packed in bytes,
+// bytes are byte aligned, the other types, the arrays and the structures start at even bytes.
+func ParseDBSources(sources ...io.Reader) ([]DataBlock, error) {
+	p := &parser{types: make(map[string]*typeDef)}
+	for _, source := range sources {
+		contents, err := io.ReadAll(source)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.parse(tokenize(string(contents))); err != nil {
+			return nil, err
+		}
+	}
+
+	dataBlocks := make([]DataBlock, 0, len(p.blocks))
+	for _, block := range p.blocks {
+		l := &layout{types: p.types}
+		if err := l.place("", block.typ, false, false, ""); err != nil {
+			return nil, fmt.Errorf("DB %s: %v", block.name, err)
+		}
+		l.alignWord()
+		dataBlocks = append(dataBlocks, DataBlock{Name: block.name, Number: block.number, Size: l.offset, Variables: l.variables})
+	}
+	return dataBlocks, nil
+}
+
+// typeDef is a declared data type
+type typeDef struct {
+	name    string // elementary type, STRING, WSTRING, ARRAY, STRUCT or the name of a UDT
+	length  int    // of STRING and WSTRING
+	dims    [][2]int
+	elem    *typeDef
+	members []*memberDef
+}
+
+// memberDef is a declared member of a structure
+type memberDef struct {
+	name     string
+	typ      *typeDef
+	readOnly bool
+	hidden   bool
+	comment  string
+}
+
+type blockDef struct {
+	name   string
+	number int
+	typ    *typeDef
+}
+
+// token kinds
+const (
+	tokIdent = iota
+	tokQuoted
+	tokString
+	tokPunct
+	tokComment
+	tokAttributes
+	tokEOF
+)
+
+type token struct {
+	kind int
+	text string
+	line int
+}
+
+// upper returns the keyword of identifiers
+func (t token) upper() string {
+	if t.kind != tokIdent {
+		return ""
+	}
+	return strings.ToUpper(t.text)
+}
+
+// tokenize splits a source into identifiers, quoted names, strings, punctuation, comments and attribute blocks
+func tokenize(source string) []token {
+	var tokens []token
+	line := 1
+	for i := 0; i < len(source); {
+		c := source[i]
+		start := i
+		switch {
+		case c == '\n':
+			line++
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				end = len(source) - i
+			}
+			tokens = append(tokens, token{kind: tokComment, text: strings.TrimSpace(source[i+2 : i+end]), line: line})
+			i += end
+			continue
+		case strings.HasPrefix(source[i:], "(*"):
+			end := strings.Index(source[i:], "*)")
+			if end < 0 {
+				end = len(source) - i - 2
+			}
+			line += strings.Count(source[i:i+end], "\n")
+			i += end + 2
+			continue
+		case c == '{' || c == '"' || c == '\'':
+			closing := map[byte]byte{'{': '}', '"': '"', '\'': '\''}[c]
+			end := strings.IndexByte(source[i+1:], closing)
+			if end < 0 {
+				end = len(source) - i - 1
+			}
+			text := source[i+1 : i+1+end]
+			kind := map[byte]int{'{': tokAttributes, '"': tokQuoted, '\'': tokString}[c]
+			tokens = append(tokens, token{kind: kind, text: text, line: line})
+			line += strings.Count(text, "\n")
+			i += end + 2
+			continue
+		case isIdentChar(c):
+			for i < len(source) && (isIdentChar(source[i]) ||
+				// decimals, not the ranges like 0..9
+				source[i] == '.' && i+1 < len(source) && source[i+1] >= '0' && source[i+1] <= '9' && source[start] >= '0' && source[start] <= '9') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: source[start:i], line: line})
+			continue
+		case strings.HasPrefix(source[i:], "..") || strings.HasPrefix(source[i:], ":="):
+			i += 2
+		default:
+			i++
+		}
+		tokens = append(tokens, token{kind: tokPunct, text: source[start:i], line: line})
+	}
+	return append(tokens, token{kind: tokEOF, line: line})
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '#' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// attributeRegexp matches the attributes like ExternalWritable := 'False'
+var attributeRegexp = regexp.MustCompile(`(\w+)\s*:=\s*'([^']*)'`)
+
+// parseAttributes returns the attributes of a { ... } block by their upper case names
+func parseAttributes(text string) map[string]string {
+	attributes := make(map[string]string)
+	for _, match := range attributeRegexp.FindAllStringSubmatch(text, -1) {
+		attributes[strings.ToUpper(match[1])] = strings.ToUpper(match[2])
+	}
+	return attributes
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	types  map[string]*typeDef
+	blocks []blockDef
+}
+
+// peek returns the next token, skipping the comments
+func (p *parser) peek() token {
+	for p.tokens[p.pos].kind == tokComment {
+		p.pos++
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(text string) error {
+	if t := p.next(); !strings.EqualFold(t.text, text) || t.kind == tokQuoted || t.kind == tokString {
+		return fmt.Errorf("line %d: expected %s, found %q", t.line, text, t.text)
+	}
+	return nil
+}
+
+// skipTo skips the tokens up to and including the keyword
+func (p *parser) skipTo(keyword string) error {
+	for {
+		t := p.next()
+		if t.kind == tokEOF {
+			return fmt.Errorf("line %d: %s is missing", t.line, keyword)
+		}
+		if t.upper() == keyword {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parse(tokens []token) error {
+	p.tokens, p.pos = tokens, 0
+	for {
+		t := p.next()
+		switch t.upper() {
+		case "TYPE":
+			name, err := p.blockName("UDT")
+			if err != nil {
+				return err
+			}
+			if err = p.skipTo("STRUCT"); err != nil {
+				return err
+			}
+			typ, err := p.parseStruct()
+			if err != nil {
+				return fmt.Errorf("UDT %s: %v", name, err)
+			}
+			p.types[strings.ToUpper(name)] = typ
+			if err = p.skipTo("END_TYPE"); err != nil {
+				return err
+			}
+		case "DATA_BLOCK":
+			if err := p.parseDataBlock(); err != nil {
+				return err
+			}
+		case "FUNCTION_BLOCK", "FUNCTION", "ORGANIZATION_BLOCK":
+			if err := p.skipTo("END_" + t.upper()); err != nil {
+				return err
+			}
+		}
+		if t.kind == tokEOF {
+			return nil
+		}
+	}
+}
+
+// blockName parses a symbolic name or the block type and number, like DB 4 or UDT 1
+func (p *parser) blockName(blockType string) (string, error) {
+	t := p.next()
+	if t.kind == tokQuoted {
+		return t.text, nil
+	}
+	name := strings.ToUpper(t.text)
+	if name == blockType {
+		name += p.next().text
+	}
+	if _, err := strconv.Atoi(strings.TrimPrefix(name, blockType)); t.kind != tokIdent || err != nil {
+		return "", fmt.Errorf("line %d: invalid %s name %q", t.line, blockType, t.text)
+	}
+	return name, nil
+}
+
+func (p *parser) parseDataBlock() error {
+	name, err := p.blockName("DB")
+	if err != nil {
+		return err
+	}
+	block := blockDef{name: name}
+	if number, err := strconv.Atoi(strings.TrimPrefix(name, "DB")); err == nil && strings.HasPrefix(name, "DB") {
+		block.number = number
+	}
+
+	// the header holds the attributes, the title, the version and the structure or the type of the DB
+	var instanceOf string
+	for block.typ == nil {
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return fmt.Errorf("DB %s: BEGIN is missing", name)
+		case t.kind == tokAttributes:
+			if parseAttributes(t.text)["S7_OPTIMIZED_ACCESS"] == "TRUE" {
+				return fmt.Errorf("DB %s has optimized access, its variables have no absolute address", name)
+			}
+		case t.upper() == "TITLE":
+			for p.peek().line == t.line && p.peek().kind != tokEOF {
+				p.next()
+			}
+		case t.upper() == "VERSION" || t.upper() == "AUTHOR" || t.upper() == "FAMILY" || t.upper() == "NAME":
+			p.next() // :
+			p.next()
+		case t.upper() == "STRUCT":
+			if block.typ, err = p.parseStruct(); err != nil {
+				return fmt.Errorf("DB %s: %v", name, err)
+			}
+		case t.kind == tokQuoted:
+			instanceOf = t.text
+		case t.upper() == "UDT" || t.upper() == "FB" || t.upper() == "SFB":
+			instanceOf = t.upper() + p.next().text
+		case t.upper() == "BEGIN":
+			if instanceOf == "" {
+				return fmt.Errorf("DB %s has no structure", name)
+			}
+			block.typ = &typeDef{name: instanceOf}
+			p.pos--
+		}
+	}
+	p.blocks = append(p.blocks, block)
+	return p.skipTo("END_DATA_BLOCK")
+}
+
+// parseStruct parses the members up to END_STRUCT
+func (p *parser) parseStruct() (*typeDef, error) {
+	typ := &typeDef{name: "STRUCT"}
+	for {
+		t := p.next()
+		switch {
+		case t.upper() == "END_STRUCT":
+			return typ, nil
+		case t.kind == tokPunct && t.text == ";":
+			continue
+		case t.kind != tokIdent && t.kind != tokQuoted:
+			return nil, fmt.Errorf("line %d: expected a member name, found %q", t.line, t.text)
+		}
+
+		member := &memberDef{name: t.text}
+		if p.peek().kind == tokAttributes {
+			attributes := parseAttributes(p.next().text)
+			member.hidden = attributes["EXTERNALACCESSIBLE"] == "FALSE"
+			member.readOnly = attributes["EXTERNALWRITABLE"] == "FALSE"
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		var err error
+		if member.typ, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		// skip the initial value
+		end := p.peek()
+		if end.text == ":=" {
+			for depth := 0; end.kind != tokEOF && (end.text != ";" || depth > 0); end = p.peek() {
+				if end.text == "(" || end.text == "[" {
+					depth++
+				} else if end.text == ")" || end.text == "]" {
+					depth--
+				}
+				p.next()
+			}
+		}
+		if err = p.expect(";"); err != nil {
+			return nil, err
+		}
+		if comment := p.tokens[p.pos]; comment.kind == tokComment && comment.line == end.line {
+			member.comment = comment.text
+		}
+		typ.members = append(typ.members, member)
+	}
+}
+
+func (p *parser) parseType() (*typeDef, error) {
+	t := p.next()
+	if t.kind == tokQuoted {
+		return &typeDef{name: t.text}, nil
+	}
+	name := t.upper()
+	switch name {
+	case "STRUCT":
+		return p.parseStruct()
+	case "UDT":
+		return &typeDef{name: name + p.next().text}, nil
+	case "STRING", "WSTRING":
+		typ := &typeDef{name: name, length: 254}
+		if p.peek().text == "[" {
+			p.next()
+			length, err := strconv.Atoi(p.next().text)
+			if err != nil || length < 0 || length > 254 {
+				return nil, fmt.Errorf("line %d: invalid %s length", t.line, name)
+			}
+			typ.length = length
+			if err = p.expect("]"); err != nil {
+				return nil, err
+			}
+		}
+		return typ, nil
+	case "ARRAY":
+		typ := &typeDef{name: name}
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+		for {
+			low, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(".."); err != nil {
+				return nil, err
+			}
+			high, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			if high < low {
+				return nil, fmt.Errorf("line %d: invalid array range %d..%d", t.line, low, high)
+			}
+			typ.dims = append(typ.dims, [2]int{low, high})
+			if p.peek().text != "," {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("OF"); err != nil {
+			return nil, err
+		}
+		var err error
+		typ.elem, err = p.parseType()
+		return typ, err
+	}
+	if _, ok := elementarySizes[name]; !ok && name != "BOOL" {
+		return nil, fmt.Errorf("line %d: unknown data type %q", t.line, t.text)
+	}
+	return &typeDef{name: name}, nil
+}
+
+// parseInt parses an array limit, which can be negative
+func (p *parser) parseInt() (int, error) {
+	t := p.next()
+	text := t.text
+	if text == "-" {
+		text += p.next().text
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: invalid array limit %q", t.line, text)
+	}
+	return n, nil
+}
+
+// layout places the variables at their absolute offsets
+type layout struct {
+	types     map[string]*typeDef
+	offset    int
+	bit       int
+	variables []Variable
+}
+
+// alignByte moves to the next byte after the BOOLs
+func (l *layout) alignByte() {
+	if l.bit > 0 {
+		l.offset++
+		l.bit = 0
+	}
+}
+
+// alignWord moves to the next even byte
+func (l *layout) alignWord() {
+	l.alignByte()
+	l.offset += l.offset % 2
+}
+
+func (l *layout) add(path string, dataType string, size int, readOnly bool, comment string) {
+	l.variables = append(l.variables, Variable{
+		Name:     path,
+		DataType: dataType,
+		Offset:   l.offset,
+		Bit:      l.bit,
+		Size:     size,
+		ReadOnly: readOnly,
+		Comment:  comment,
+	})
+}
+
+// place lays out a variable of the type, hidden variables take their place without being added
+func (l *layout) place(path string, typ *typeDef, readOnly bool, hidden bool, comment string) error {
+	switch typ.name {
+	case "STRUCT":
+		l.alignWord()
+		for _, member := range typ.members {
+			name := member.name
+			if path != "" {
+				name = path + "." + name
+			}
+			if err := l.place(name, member.typ, readOnly || member.readOnly, hidden || member.hidden, member.comment); err != nil {
+				return err
+			}
+		}
+		l.alignWord()
+	case "ARRAY":
+		count := 1
+		for _, dim := range typ.dims {
+			count *= dim[1] - dim[0] + 1
+			if count > maxArrayElements {
+				return fmt.Errorf("array %s has more than %d elements", path, maxArrayElements)
+			}
+		}
+		l.alignWord()
+		index := make([]int, len(typ.dims))
+		for i := range index {
+			index[i] = typ.dims[i][0]
+		}
+		for n := 0; n < count; n++ {
+			indexes := make([]string, len(index))
+			for i := range index {
+				indexes[i] = strconv.Itoa(index[i])
+			}
+			if err := l.place(path+"["+strings.Join(indexes, ",")+"]", typ.elem, readOnly, hidden, comment); err != nil {
+				return err
+			}
+			// the last index varies fastest
+			for i := len(index) - 1; i >= 0; i-- {
+				if index[i]++; index[i] <= typ.dims[i][1] {
+					break
+				}
+				index[i] = typ.dims[i][0]
+			}
+		}
+		l.alignWord()
+	case "STRING", "WSTRING":
+		l.alignWord()
+		size := typ.length + 2
+		if typ.name == "WSTRING" {
+			size = 2*typ.length + 4
+		}
+		if !hidden {
+			l.add(path, fmt.Sprintf("%s[%d]", typ.name, typ.length), size, readOnly, comment)
+		}
+		l.offset += size
+	case "BOOL":
+		if !hidden {
+			l.add(path, typ.name, 0, readOnly, comment)
+		}
+		if l.bit++; l.bit == 8 {
+			l.alignByte()
+		}
+	default:
+		size, ok := elementarySizes[typ.name]
+		if !ok {
+			udt, ok := l.types[strings.ToUpper(typ.name)]
+			if !ok {
+				return fmt.Errorf("unknown type %s of %s, instance DBs of function blocks are not supported", typ.name, path)
+			}
+			return l.place(path, udt, readOnly, hidden, comment)
+		}
+		if size == 1 {
+			l.alignByte()
+		} else {
+			l.alignWord()
+		}
+		if !hidden {
+			l.add(path, typ.name, size, readOnly, comment)
+		}
+		l.offset += size
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package symbols
+
+import (
+	"strings"
+	"testing"
+)
+
+const tiaSource = `TYPE "Motor"
+VERSION : 0.1
+   STRUCT
+      Running { ExternalWritable := 'False'} : Bool;   // motor is running
+      Speed : Real;
+   END_STRUCT;
+
+END_TYPE
+
+DATA_BLOCK "Tank1"
+{ S7_Optimized_Access := 'FALSE' }
+VERSION : 0.1
+NON_RETAIN
+   STRUCT
+      Level : Real;   // level in m
+      Valve : Bool;
+      Pump : Bool := true;
+      Mode : Byte;
+      Count : Int := 5;
+      Name : String[5];
+      Flags : Array[0..9] of Bool;
+      Recipe : Array[1..2] of Struct
+         Setpoint : Int;
+         Enabled : Bool;
+      END_STRUCT;
+      Agitator : "Motor";
+      Secret { ExternalAccessible := 'False'} : DInt;
+      Delay : S5Time;
+      Total : LReal;
+   END_STRUCT;
+
+
+BEGIN
+   Level := 1.5;
+   Recipe[1].Setpoint := 10;
+
+END_DATA_BLOCK
+`
+
+const classicSource = `DATA_BLOCK DB 4
+TITLE =Line data
+AUTHOR : Ops
+VERSION : 0.1
+
+
+  STRUCT
+   Speed : INT ;	//line speed
+   Matrix : ARRAY  [1 .. 2, -1 .. 0 ] OF BYTE ;
+   Ready : BOOL ;
+  END_STRUCT ;
+BEGIN
+   Speed := 0;
+END_DATA_BLOCK
+`
+
+func TestParseDBSources(t *testing.T) {
+	blocks, err := ParseDBSources(strings.NewReader(tiaSource), strings.NewReader(classicSource))
+	if err != nil {
+		t.Fatalf("ParseDBSources() error = %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("%d data blocks, want 2", len(blocks))
+	}
+
+	tank := blocks[0]
+	if tank.Name != "Tank1" || tank.Number != 0 || tank.Size != 46 {
+		t.Errorf("data block = %s %d, size %d, want Tank1 0, size 46", tank.Name, tank.Number, tank.Size)
+	}
+	want := map[string]string{
+		"Level":              "DB10.DBD0",
+		"Valve":              "DB10.DBX4.0",
+		"Pump":               "DB10.DBX4.1",
+		"Mode":               "DB10.DBB5",
+		"Count":              "DB10.DBW6",
+		"Name":               "DB10.DBD8",
+		"Flags[0]":           "DB10.DBX16.0",
+		"Flags[9]":           "DB10.DBX17.1",
+		"Recipe[1].Setpoint": "DB10.DBW18",
+		"Recipe[1].Enabled":  "DB10.DBX20.0",
+		"Recipe[2].Setpoint": "DB10.DBW22",
+		"Agitator.Running":   "DB10.DBX26.0",
+		"Agitator.Speed":     "DB10.DBD28",
+		"Delay":              "DB10.DBW36",
+		"Total":              "DB10.DBD38",
+	}
+	got := make(map[string]Variable)
+	for _, v := range tank.Variables {
+		got[v.Name] = v
+	}
+	for name, address := range want {
+		if v, ok := got[name]; !ok || v.Address(10) != address {
+			t.Errorf("address of %s = %s, want %s", name, v.Address(10), address)
+		}
+	}
+	if _, ok := got["Secret"]; ok {
+		t.Errorf("variable Secret is not accessible")
+	}
+	if !got["Agitator.Running"].ReadOnly || got["Agitator.Speed"].ReadOnly {
+		t.Errorf("only Agitator.Running should be read-only")
+	}
+	if got["Level"].Comment != "level in m" || got["Name"].DataType != "STRING[5]" {
+		t.Errorf("Level = %+v, Name = %+v", got["Level"], got["Name"])
+	}
+
+	line := blocks[1]
+	if line.Name != "DB4" || line.Number != 4 || line.Size != 8 || len(line.Variables) != 6 {
+		t.Fatalf("data block = %+v", line)
+	}
+	addresses := make([]string, len(line.Variables))
+	for i, v := range line.Variables {
+		addresses[i] = v.Name + "=" + v.Address(line.Number)
+	}
+	if got := strings.Join(addresses, " "); got != "Speed=DB4.DBW0 Matrix[1,-1]=DB4.DBB2 Matrix[1,0]=DB4.DBB3 Matrix[2,-1]=DB4.DBB4 Matrix[2,0]=DB4.DBB5 Ready=DB4.DBX6.0" {
+		t.Errorf("variables = %s", got)
+	}
+	if line.Variables[0].Comment != "line speed" {
+		t.Errorf("comment of Speed = %q", line.Variables[0].Comment)
+	}
+}
+
+func TestParseDBSourcesErrors(t *testing.T) {
+	for name, source := range map[string]string{
+		"optimized":    "DATA_BLOCK \"A\"\n{ S7_Optimized_Access := 'TRUE' }\nSTRUCT\nX : Int;\nEND_STRUCT;\nBEGIN\nEND_DATA_BLOCK",
+		"unknown type": "DATA_BLOCK DB 1\nSTRUCT\nX : Float;\nEND_STRUCT;\nBEGIN\nEND_DATA_BLOCK",
+		"instance DB":  "DATA_BLOCK DB 1\n\"FB_Motor\"\nBEGIN\nEND_DATA_BLOCK",
+		"unterminated": "DATA_BLOCK DB 1\nSTRUCT\nX : Int;\n",
+	} {
+		if _, err := ParseDBSources(strings.NewReader(source)); err == nil {
+			t.Errorf("ParseDBSources() of %s should fail", name)
+		}
+	}
+}
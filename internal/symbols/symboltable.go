@@ -0,0 +1,222 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+//
+// Copyright (C) 2026 YIQISOFT
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package symbols
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Formats of the symbol tables
+const (
+	FormatSDF = "SDF" // STEP 7 Classic, System Data Format: quoted and comma separated
+	FormatSEQ = "SEQ" // STEP 7 Classic, assignment list: tab separated
+	FormatASC = "ASC" // STEP 7 Classic, fixed columns
+	FormatCSV = "CSV" // TIA Portal PLC tag table
+)
+
+// columns of the ASC format after the 126, record type
+const (
+	ascSymbolWidth   = 24
+	ascAddressWidth  = 12
+	ascDataTypeWidth = 10
+)
+
+// Symbol is a symbolic name of an absolute address
+type Symbol struct {
+	Name     string
+	Address  string // without spaces and %, like Q4.0, MW10 or DB10 for the symbols of the blocks
+	DataType string // upper case, like BOOL or INT, or the block type like DB
+	Comment  string
+}
+
+// SymbolTableFormat returns the symbol table format of a file extension
+func SymbolTableFormat(path string) (string, bool) {
+	format := strings.ToUpper(strings.TrimPrefix(filepath.Ext(path), "."))
+	switch format {
+	case FormatSDF, FormatSEQ, FormatASC, FormatCSV:
+		return format, true
+	}
+	return "", false
+}
+
+// ParseSymbolTable parses a symbol table of the format, the STEP 7 Classic exports
+// are ANSI encoded and the TIA Portal exports are UTF-8
+func ParseSymbolTable(r io.Reader, format string) ([]Symbol, error) {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	contents = bytes.TrimPrefix(contents, []byte("\xEF\xBB\xBF"))
+	if !utf8.Valid(contents) {
+		contents = latin1ToUTF8(contents)
+	}
+
+	switch format {
+	case FormatSDF:
+		return parseSDF(contents)
+	case FormatSEQ, FormatASC:
+		return parseAssignmentList(contents, format)
+	case FormatCSV:
+		return parseTagTable(contents)
+	}
+	return nil, fmt.Errorf("unknown symbol table format %s", format)
+}
+
+func latin1ToUTF8(contents []byte) []byte {
+	runes := make([]rune, len(contents))
+	for i, b := range contents {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes))
+}
+
+// newSymbol normalizes the address and the data type, the addresses like "Q       4.0" or %QW2
+// lose their spaces and %, and the symbols of the blocks get the block type, like DB
+func newSymbol(name string, address string, dataType string, comment string) Symbol {
+	address = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(address), " ", ""))
+	dataType = strings.ToUpper(strings.TrimSpace(dataType))
+	for _, block := range []string{"DB", "FB", "FC", "OB", "SFB", "SFC", "UDT", "VAT"} {
+		if strings.HasPrefix(address, block) && strings.HasPrefix(dataType, block) {
+			dataType = block
+			break
+		}
+	}
+	return Symbol{
+		Name:     strings.TrimSpace(name),
+		Address:  strings.TrimPrefix(address, "%"),
+		DataType: strings.ReplaceAll(dataType, " ", ""),
+		Comment:  strings.TrimSpace(comment),
+	}
+}
+
+// parseSDF parses the lines like "Motor1_Run","Q       4.0","BOOL","Motor 1 running"
+func parseSDF(contents []byte) ([]Symbol, error) {
+	reader := csv.NewReader(bytes.NewReader(contents))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	var symbols []Symbol
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return symbols, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("line %d: expected the symbol, the address and the data type", line)
+		}
+		var comment string
+		if len(record) > 3 {
+			comment = record[3]
+		}
+		symbols = append(symbols, newSymbol(record[0], record[1], record[2], comment))
+	}
+}
+
+// parseAssignmentList parses the lines of 126, records, with tab separated or fixed columns
+func parseAssignmentList(contents []byte, format string) ([]Symbol, error) {
+	var symbols []Symbol
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		record, found := strings.CutPrefix(text, "126,")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected a 126, record", line)
+		}
+
+		var fields []string
+		if format == FormatSEQ {
+			fields = strings.Split(record, "\t")
+		} else {
+			// the columns are counted in characters, not in the bytes of the umlauts
+			columns := []rune(record)
+			for _, width := range []int{ascSymbolWidth, ascAddressWidth, ascDataTypeWidth} {
+				width = min(width, len(columns))
+				fields = append(fields, string(columns[:width]))
+				columns = columns[width:]
+			}
+			fields = append(fields, string(columns))
+		}
+		for len(fields) < 4 {
+			fields = append(fields, "")
+		}
+		if strings.TrimSpace(fields[0]) == "" || strings.TrimSpace(fields[1]) == "" {
+			return nil, fmt.Errorf("line %d: expected the symbol and the address", line)
+		}
+		symbols = append(symbols, newSymbol(fields[0], fields[1], fields[2], strings.Join(fields[3:], " ")))
+	}
+	return symbols, scanner.Err()
+}
+
+// tagTableColumns are the German column names of the tag tables
+var tagTableColumns = map[string]string{
+	"datentyp":         "data type",
+	"logische adresse": "logical address",
+	"kommentar":        "comment",
+}
+
+// parseTagTable parses a TIA Portal PLC tag table, the columns are found by the header,
+// like Name,Path,Data Type,Logical Address,Comment
+func parseTagTable(contents []byte) ([]Symbol, error) {
+	reader := csv.NewReader(bytes.NewReader(contents))
+	reader.FieldsPerRecord = -1
+	// the German exports are separated by semicolons
+	if header, _, _ := strings.Cut(string(contents), "\n"); strings.Count(header, ";") > strings.Count(header, ",") {
+		reader.Comma = ';'
+	}
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read the header of the tag table failed, error: %v", err)
+	}
+	columns := map[string]int{"name": -1, "data type": -1, "logical address": -1, "comment": -1}
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if alias, ok := tagTableColumns[column]; ok {
+			column = alias
+		}
+		if _, ok := columns[column]; ok {
+			columns[column] = i
+		}
+	}
+	for _, column := range []string{"name", "data type", "logical address"} {
+		if columns[column] < 0 {
+			return nil, fmt.Errorf("the tag table has no %s column", column)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		if i := columns[column]; i >= 0 && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+	var symbols []Symbol
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return symbols, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if field(record, "name") == "" {
+			continue
+		}
+		symbols = append(symbols, newSymbol(field(record, "name"), field(record, "logical address"), field(record, "data type"), field(record, "comment")))
+	}
+}
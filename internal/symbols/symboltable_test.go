@@ -0,0 +1,97 @@
+package symbols
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseSymbolTable(t *testing.T) {
+	want := []Symbol{
+		{Name: "Motor1_Run", Address: "Q4.0", DataType: "BOOL", Comment: "Motor 1 running"},
+		{Name: "Speed", Address: "MW10", DataType: "INT", Comment: "Drehzahl Förderband"},
+		{Name: "Füllstand Tank/1", Address: "MW12", DataType: "INT", Comment: "Level"},
+		{Name: "Tank1", Address: "DB10", DataType: "DB"},
+	}
+	tests := []struct {
+		name   string
+		format string
+		table  string
+	}{
+		{
+			name:   "SDF",
+			format: FormatSDF,
+			table: "\"Motor1_Run\",\"Q       4.0\",\"BOOL\",\"Motor 1 running\"\r\n" +
+				"\"Speed\",\"MW     10\",\"INT\",\"Drehzahl F\xF6rderband\"\r\n" +
+				"\"F\xFCllstand Tank/1\",\"MW     12\",\"INT\",\"Level\"\r\n" +
+				"\"Tank1\",\"DB     10\",\"DB     10\",\"\"\r\n",
+		},
+		{
+			name:   "SEQ",
+			format: FormatSEQ,
+			table: "126,Motor1_Run\tQ       4.0\tBOOL\tMotor 1 running\r\n" +
+				"126,Speed\tMW     10\tINT\tDrehzahl F\xF6rderband\r\n" +
+				"126,F\xFCllstand Tank/1\tMW     12\tINT\tLevel\r\n" +
+				"126,Tank1\tDB     10\tDB     10\t\r\n",
+		},
+		{
+			name:   "ASC",
+			format: FormatASC,
+			table: fmt.Sprintf("126,%-24s%-12s%-10s%-80s\r\n", "Motor1_Run", "Q       4.0", "BOOL", "Motor 1 running") +
+				fmt.Sprintf("126,%-24s%-12s%-10s%-80s\r\n", "Speed", "MW     10", "INT", "Drehzahl F\xF6rderband") +
+				fmt.Sprintf("126,%-24s%-12s%-10s%-80s\r\n", "F\xFCllstand Tank/1", "MW     12", "INT", "Level") +
+				fmt.Sprintf("126,%-24s%-12s%-10s%-80s\r\n", "Tank1", "DB     10", "DB     10", ""),
+		},
+		{
+			name:   "TIA CSV",
+			format: FormatCSV,
+			table: "\xEF\xBB\xBFName,Path,Data Type,Logical Address,Comment,Hmi Visible,Hmi Accessible\n" +
+				"\"Motor1_Run\",\"Default tag table\",\"Bool\",\"%Q4.0\",\"Motor 1 running\",\"True\",\"True\"\n" +
+				"\"Speed\",\"Default tag table\",\"Int\",\"%MW10\",\"Drehzahl Förderband\",\"True\",\"True\"\n" +
+				"\"Füllstand Tank/1\",\"Default tag table\",\"Int\",\"%MW12\",\"Level\",\"True\",\"True\"\n" +
+				"\"Tank1\",\"Default tag table\",\"DB\",\"DB10\",,\"True\",\"True\"\n",
+		},
+		{
+			name:   "German TIA CSV",
+			format: FormatCSV,
+			table: "Name;Pfad;Datentyp;Logische Adresse;Kommentar\n" +
+				"Motor1_Run;Standard-Variablentabelle;Bool;%A4.0;Motor 1 running\n" +
+				"Speed;Standard-Variablentabelle;Int;%MW10;Drehzahl Förderband\n" +
+				"Füllstand Tank/1;Standard-Variablentabelle;Int;%MW12;Level\n" +
+				"Tank1;Standard-Variablentabelle;DB;DB10;\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSymbolTable(strings.NewReader(tt.table), tt.format)
+			if err != nil {
+				t.Fatalf("ParseSymbolTable() error = %v", err)
+			}
+			expected := want
+			if tt.name == "German TIA CSV" {
+				expected = append([]Symbol{{Name: "Motor1_Run", Address: "A4.0", DataType: "BOOL", Comment: "Motor 1 running"}}, want[1:]...)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(expected) {
+				t.Errorf("ParseSymbolTable() = %v, want %v", got, expected)
+			}
+		})
+	}
+}
+
+func TestParseSymbolTableErrors(t *testing.T) {
+	for format, table := range map[string]string{
+		FormatSDF: "\"Motor1_Run\",\"Q 4.0\"\n",
+		FormatSEQ: "Motor1_Run\tQ 4.0\tBOOL\n",
+		FormatCSV: "Name,Comment\nMotor1_Run,\n",
+	} {
+		if _, err := ParseSymbolTable(strings.NewReader(table), format); err == nil {
+			t.Errorf("ParseSymbolTable() of an invalid %s table should fail", format)
+		}
+	}
+	if format, ok := SymbolTableFormat("plant/Symbols.Sdf"); !ok || format != FormatSDF {
+		t.Errorf("SymbolTableFormat() = %s, %v", format, ok)
+	}
+	if _, ok := SymbolTableFormat("Tank1.db"); ok {
+		t.Errorf("a DB source is not a symbol table")
+	}
+}
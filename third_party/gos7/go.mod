@@ -0,0 +1,3 @@
+module github.com/robinson/gos7
+
+go 1.21
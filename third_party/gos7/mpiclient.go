@@ -0,0 +1,5 @@
+package gos7
+
+//MPI: multi point interface is RS485 based using in Siemens S7-300 and S7-400 PLCs
+//https://de.wikipedia.org/wiki/Multi_Point_Interface
+//todo: under construction
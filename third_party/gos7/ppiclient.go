@@ -0,0 +1,5 @@
+package gos7
+
+//PPI: Point to Point Interface, is RS485 based
+//use for Simatic-S7-200
+//TODO: under contruction